@@ -3,14 +3,24 @@ package main
 import (
 	"context"
 	"database-final-project/internal/answer"
+	"database-final-project/internal/apikey"
+	"database-final-project/internal/auth"
+	"database-final-project/internal/collaborator"
 	"database-final-project/internal/config"
 	"database-final-project/internal/cors"
 	"database-final-project/internal/database"
+	"database-final-project/internal/export"
 	"database-final-project/internal/form"
+	"database-final-project/internal/invitation"
 	loguril "database-final-project/internal/logger"
 	"database-final-project/internal/options"
 	"database-final-project/internal/question"
+	"database-final-project/internal/section"
+	"database-final-project/internal/session"
 	"database-final-project/internal/submission"
+	"database-final-project/internal/summary"
+	"database-final-project/internal/user"
+	"database-final-project/internal/version"
 	"errors"
 	"log"
 	"net/http"
@@ -51,37 +61,73 @@ func main() {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
-	optionsQuerier := options.New(dbPool)
-	optionsStore := options.NewService(logger, optionsQuerier)
+	db := database.NewDB(dbPool)
+
+	userQuerier := user.New(db)
+	userService := user.NewService(logger, userQuerier)
 
-	answerQuerier := answer.New(dbPool)
-	answerService := answer.NewService(logger, answerQuerier, optionsStore)
+	sessionQuerier := session.New(db)
+	sessionService := session.NewService(logger, sessionQuerier)
+	userHandler := user.NewHandler(logger, userService, sessionService)
 
-	submissionQuerier := submission.New(dbPool)
-	submissionService := submission.NewService(logger, submissionQuerier, answerService)
+	apiKeyQuerier := apikey.New(db)
+	apiKeyService := apikey.NewService(logger, apiKeyQuerier)
+	apiKeyHandler := apikey.NewHandler(logger, apiKeyService)
+
+	optionsQuerier := options.New(db)
+	optionsStore := options.NewService(logger, optionsQuerier)
 
-	questionQuerier := question.New(dbPool)
+	answerQuerier := answer.New(db)
+	answerService := answer.NewService(logger, answerQuerier)
+
+	questionQuerier := question.New(db)
 	questionService := question.NewService(logger, questionQuerier, optionsStore)
 
-	formQuerier := form.New(dbPool)
-	formService := form.NewService(logger, formQuerier, questionService)
-	formHandler := form.NewHandler(logger, formService, submissionService)
+	sectionQuerier := section.New(db)
+	sectionService := section.NewService(logger, sectionQuerier)
+
+	versionQuerier := version.New(db)
+	versionService := version.NewService(logger, versionQuerier)
+
+	collaboratorQuerier := collaborator.New(db)
+	collaboratorService := collaborator.NewService(logger, collaboratorQuerier)
+
+	invitationQuerier := invitation.New(db)
+	invitationService := invitation.NewService(logger, invitationQuerier)
+
+	formQuerier := form.New(db)
+	formService := form.NewService(logger, formQuerier, db, questionService, sectionService, versionService, collaboratorService, invitationService)
+
+	submissionQuerier := submission.New(db)
+	submissionService := submission.NewService(logger, submissionQuerier, db, answerService, versionService, formService)
+
+	summaryQuerier := summary.New(db)
+	summaryService := summary.NewService(logger, summaryQuerier, versionService)
+
+	exportService := export.NewService(logger, submissionService, versionService)
+
+	formHandler := form.NewHandler(logger, formService, submissionService, summaryService, exportService, collaboratorService, invitationService)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /api/forms", formHandler.GetAll)
-	mux.HandleFunc("GET /api/forms/{id}", formHandler.GetByID)
-	mux.HandleFunc("POST /api/forms", formHandler.Create)
-	mux.HandleFunc("PUT /api/forms/{id}", formHandler.Update)
-	mux.HandleFunc("DELETE /api/forms/{id}", formHandler.Delete)
-	mux.HandleFunc("GET /api/forms/{id}/answers", formHandler.GetAllAnswer)
-	mux.HandleFunc("POST /api/forms/{id}/answers", formHandler.CreateAnswer)
+	mux.HandleFunc("POST /api/users", userHandler.Register)
+	mux.HandleFunc("GET /api/users/me", userHandler.GetCurrent)
+	mux.HandleFunc("POST /api/sessions", userHandler.SignIn)
+	mux.HandleFunc("DELETE /api/sessions/current", userHandler.SignOut)
+	mux.HandleFunc("GET /api/keys", apiKeyHandler.GetAll)
+	mux.HandleFunc("POST /api/keys", apiKeyHandler.Create)
+	mux.HandleFunc("DELETE /api/keys/{id}", apiKeyHandler.Delete)
+
+	formHandler.RegisterRoutes(mux)
 
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	entrypoint := cors.CORSMiddleware(mux.ServeHTTP, logger, []string{"*"})
+	// API keys are used by services rather than browsers, so they are
+	// authenticated ahead of the CORS handling that sessions go through.
+	entrypoint := cors.CORSMiddleware(auth.Middleware(mux.ServeHTTP, logger, sessionService), logger, []string{"*"})
+	entrypoint = auth.KeyMiddleware(entrypoint, logger, apiKeyService)
 
 	srv := &http.Server{Addr: ":8080", Handler: http.HandlerFunc(entrypoint)}
 
@@ -1,13 +1,15 @@
--- name: GetBySubmissionID :many
-SELECT
-    a.*,
-    q.*
-FROM answers a
-JOIN questions q ON q.id = a.question_id
-WHERE a.submission_id = $1
-ORDER BY a.created_at ASC;
-
 -- name: Create :one
-INSERT INTO answers (submission_id, question_id, answer_text, answer_options)
-VALUES ($1, $2, $3, $4)
-RETURNING *;
\ No newline at end of file
+INSERT INTO answers (submission_id, question_id, answer_text, answer_options, answer_number, answer_date, answer_time)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetBySubmissionIDs :many
+SELECT *
+FROM answers
+WHERE submission_id = ANY (@submission_ids::uuid[])
+ORDER BY created_at ASC;
+
+-- name: DeleteBySubmissionID :exec
+DELETE
+FROM answers
+WHERE submission_id = $1;
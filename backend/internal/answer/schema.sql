@@ -2,9 +2,14 @@ CREATE TABLE IF NOT EXISTS answers
 (
     id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
     submission_id UUID        NOT NULL REFERENCES submissions (id) ON DELETE CASCADE,
-    question_id   UUID        NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    question_id   UUID        NOT NULL,
     answer_text   TEXT,
     answer_options UUID[],
     created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    answer_number DOUBLE PRECISION,
+    answer_date   DATE,
+    answer_time   TIME
+);
+
+CREATE INDEX IF NOT EXISTS answers_submission_id_idx ON answers (submission_id);
@@ -2,7 +2,7 @@ package answer
 
 import (
 	"context"
-	"database-final-project/internal/options"
+	"database-final-project/internal/question"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -10,64 +10,103 @@ import (
 )
 
 type Querier interface {
-	GetBySubmissionID(ctx context.Context, submissionID uuid.UUID) ([]GetBySubmissionIDRow, error)
+	GetBySubmissionIDs(ctx context.Context, submissionIds []uuid.UUID) ([]Answer, error)
 	Create(ctx context.Context, params CreateParams) (Answer, error)
-}
-
-type optionStore interface {
-	GetByQuestionID(ctx context.Context, id uuid.UUID) ([]options.Response, error)
+	DeleteBySubmissionID(ctx context.Context, submissionID uuid.UUID) error
 }
 
 type Service struct {
-	logger      *zap.Logger
-	queries     Querier
-	optionStore optionStore
+	logger  *zap.Logger
+	queries Querier
 }
 
-func NewService(logger *zap.Logger, queries Querier, optionStore optionStore) *Service {
+func NewService(logger *zap.Logger, queries Querier) *Service {
 	return &Service{
-		logger:      logger,
-		queries:     queries,
-		optionStore: optionStore,
+		logger:  logger,
+		queries: queries,
 	}
 }
 
-func (s *Service) GetBySubmissionID(ctx context.Context, submissionID uuid.UUID) ([]Response, error) {
-	answers, err := s.queries.GetBySubmissionID(ctx, submissionID)
+// GetBySubmissionIDs loads the answers of several submissions in one query and
+// renders each submission against its entry in questionsBySubmission.
+func (s *Service) GetBySubmissionIDs(ctx context.Context, submissionIDs []uuid.UUID, questionsBySubmission map[uuid.UUID][]question.OptionsQuestion) (map[uuid.UUID][]Response, error) {
+	answers, err := s.queries.GetBySubmissionIDs(ctx, submissionIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	responses := make([]Response, len(answers))
-	for i, answer := range answers {
-		os, err := s.optionStore.GetByQuestionID(ctx, answer.QuestionID)
-		if err != nil {
-			return nil, err
+	answersBySubmission := make(map[uuid.UUID][]Answer, len(submissionIDs))
+	for _, answer := range answers {
+		answersBySubmission[answer.SubmissionID] = append(answersBySubmission[answer.SubmissionID], answer)
+	}
+
+	responses := make(map[uuid.UUID][]Response, len(submissionIDs))
+	for _, submissionID := range submissionIDs {
+		responses[submissionID] = render(answersBySubmission[submissionID], questionsBySubmission[submissionID])
+	}
+
+	return responses, nil
+}
+
+func render(answers []Answer, questions []question.OptionsQuestion) []Response {
+	answersByQuestion := make(map[uuid.UUID]Answer, len(answers))
+	for _, answer := range answers {
+		answersByQuestion[answer.QuestionID] = answer
+	}
+
+	responses := make([]Response, 0, len(answers))
+	for _, q := range questions {
+		answer, ok := answersByQuestion[q.QuestionID]
+		if !ok {
+			continue
 		}
-		responses[i] = Response{
-			QuestionID:    answer.QuestionID,
-			QuestionType:  string(answer.Type),
-			IsRequired:    answer.IsRequired,
-			QuestionText:  answer.Text,
-			Options:       os,
+		responses = append(responses, Response{
+			QuestionID:    q.QuestionID,
+			QuestionType:  string(q.QuestionType),
+			IsRequired:    q.IsRequired,
+			QuestionText:  q.QuestionText,
+			Options:       q.Options,
 			AnswerOptions: answer.AnswerOptions,
 			AnswerText:    answer.AnswerText.String,
-		}
+			AnswerNumber:  toNumber(answer.AnswerNumber),
+		})
 	}
 
-	return responses, nil
+	return responses
+}
+
+func toNumber(number pgtype.Float8) *float64 {
+	if !number.Valid {
+		return nil
+	}
+	return &number.Float64
 }
 
-func (s *Service) Create(ctx context.Context, submissionID uuid.UUID, questionID uuid.UUID, answerText string, answerOptions []uuid.UUID) error {
-	_, err := s.queries.Create(ctx, CreateParams{
+func (s *Service) Create(ctx context.Context, submissionID uuid.UUID, questionID uuid.UUID, answerText string, answerOptions []uuid.UUID, value Value) error {
+	params := CreateParams{
 		SubmissionID:  submissionID,
 		QuestionID:    questionID,
 		AnswerText:    pgtype.Text{String: answerText, Valid: answerText != ""},
 		AnswerOptions: answerOptions,
-	})
+	}
+	if value.Number != nil {
+		params.AnswerNumber = pgtype.Float8{Float64: *value.Number, Valid: true}
+	}
+	if value.Date != nil {
+		params.AnswerDate = pgtype.Date{Time: *value.Date, Valid: true}
+	}
+	if value.Time != nil {
+		params.AnswerTime = pgtype.Time{Microseconds: value.Time.Microseconds(), Valid: true}
+	}
+
+	_, err := s.queries.Create(ctx, params)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (s *Service) DeleteBySubmissionID(ctx context.Context, submissionID uuid.UUID) error {
+	return s.queries.DeleteBySubmissionID(ctx, submissionID)
+}
@@ -2,6 +2,7 @@ package answer
 
 import (
 	"database-final-project/internal/options"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,6 +11,18 @@ type Request struct {
 	QuestionID    uuid.UUID   `json:"question_id"`
 	AnswerText    string      `json:"answer_text"`
 	AnswerOptions []uuid.UUID `json:"answer_options,omitempty"`
+	// Value is filled in by validation from AnswerText, never by the client.
+	Value Value `json:"-"`
+}
+
+// Value is the typed form of an answer to a number, rating, linear_scale,
+// date or time question. It is stored next to the answer text so that the
+// answers can be compared and aggregated in SQL.
+type Value struct {
+	Number *float64
+	Date   *time.Time
+	// Time is the time of day as the duration since midnight.
+	Time *time.Duration
 }
 
 type Response struct {
@@ -19,5 +32,6 @@ type Response struct {
 	IsRequired    bool               `json:"is_required"`
 	Options       []options.Response `json:"options,omitempty"`
 	AnswerText    string             `json:"answer_text"`
+	AnswerNumber  *float64           `json:"answer_number,omitempty"`
 	AnswerOptions []uuid.UUID        `json:"answer_options,omitempty"`
 }
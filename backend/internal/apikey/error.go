@@ -0,0 +1,9 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrKeyNotFound   = errors.New("API key not found")
+	ErrInvalidName   = errors.New("name must be between 1 and 255 characters")
+	ErrInvalidScopes = errors.New("scopes must list at least one of forms:read, forms:write, responses:read or responses:write")
+)
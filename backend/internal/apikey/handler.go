@@ -0,0 +1,118 @@
+package apikey
+
+import (
+	"context"
+	"database-final-project/internal"
+	"database-final-project/internal/auth"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Store interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]Response, error)
+	Create(ctx context.Context, userID uuid.UUID, name string, scopes []auth.Scope) (CreatedResponse, error)
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
+
+type Handler struct {
+	logger *zap.Logger
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		store:  store,
+	}
+}
+
+// currentUser returns the signed-in user, writing the error response when
+// there is none. Keys are managed with a session only, so a leaked key cannot
+// be used to issue more.
+func (h *Handler) currentUser(w http.ResponseWriter, r *http.Request) (auth.User, bool) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Sign in required"))
+		return auth.User{}, false
+	}
+	if user.IsAPIKey() {
+		internal.WriteResponseToBody(w, h.logger, http.StatusForbidden, internal.NewForbiddenError("API keys cannot manage API keys"))
+		return auth.User{}, false
+	}
+	return user, true
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.store.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get API keys", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get API keys"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, keys)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateRequest
+	err := internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	key, err := h.store.Create(r.Context(), user.ID, req.Name, req.Scopes)
+	if err != nil {
+		if errors.Is(err, ErrInvalidName) || errors.Is(err, ErrInvalidScopes) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to create API key", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to create API key"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, key)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid API key ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid API key ID"))
+		return
+	}
+
+	err = h.store.Revoke(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("API key not found"))
+			return
+		}
+		h.logger.Error("Failed to revoke API key", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to revoke API key"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+}
@@ -0,0 +1,16 @@
+-- name: GetByUserID :many
+SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at, id;
+
+-- name: Create :one
+INSERT INTO api_keys (user_id, name, key_hash, scopes) VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM api_keys WHERE id = $1 AND user_id = $2;
+
+-- name: Touch :one
+UPDATE api_keys k
+SET last_used_at = CURRENT_TIMESTAMP
+FROM users u
+WHERE k.key_hash = $1
+  AND u.id = k.user_id
+RETURNING k.id, k.user_id, k.scopes, u.username;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    key_hash     BYTEA        NOT NULL UNIQUE,
+    scopes       TEXT[]       NOT NULL CHECK (cardinality(scopes) > 0 AND
+                                              scopes <@ ARRAY ['forms:read', 'forms:write', 'responses:read', 'responses:write']),
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
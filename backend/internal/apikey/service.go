@@ -0,0 +1,149 @@
+package apikey
+
+import (
+	"context"
+	"database-final-project/internal"
+	"database-final-project/internal/auth"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type Querier interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]ApiKey, error)
+	Create(ctx context.Context, arg CreateParams) (ApiKey, error)
+	Delete(ctx context.Context, arg DeleteParams) (int64, error)
+	Touch(ctx context.Context, keyHash []byte) (TouchRow, error)
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (s *Service) GetByUserID(ctx context.Context, userID uuid.UUID) ([]Response, error) {
+	keys, err := s.queries.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]Response, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toResponse(key))
+	}
+
+	return responses, nil
+}
+
+// Create issues a key acting for the user within the scopes. The key is only
+// returned here; just its hash is stored.
+func (s *Service) Create(ctx context.Context, userID uuid.UUID, name string, scopes []auth.Scope) (CreatedResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 255 {
+		return CreatedResponse{}, ErrInvalidName
+	}
+
+	scopeStrs := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return CreatedResponse{}, ErrInvalidScopes
+		}
+		if !slices.Contains(scopeStrs, string(scope)) {
+			scopeStrs = append(scopeStrs, string(scope))
+		}
+	}
+	if len(scopeStrs) == 0 {
+		return CreatedResponse{}, ErrInvalidScopes
+	}
+
+	token, err := internal.NewToken()
+	if err != nil {
+		return CreatedResponse{}, err
+	}
+	token = auth.KeyPrefix + token
+
+	key, err := s.queries.Create(ctx, CreateParams{
+		UserID:  userID,
+		Name:    name,
+		KeyHash: internal.HashToken(token),
+		Scopes:  scopeStrs,
+	})
+	if err != nil {
+		return CreatedResponse{}, err
+	}
+
+	return CreatedResponse{
+		Response: toResponse(key),
+		Key:      token,
+	}, nil
+}
+
+// Revoke deletes a key of the user.
+func (s *Service) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	deleted, err := s.queries.Delete(ctx, DeleteParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate returns the user of the key, limited to its scopes, or
+// auth.ErrInvalidToken. The key is marked as used on the way.
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.User, error) {
+	key, err := s.queries.Touch(ctx, internal.HashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return auth.User{}, auth.ErrInvalidToken
+		}
+		return auth.User{}, err
+	}
+
+	return auth.User{
+		ID:       key.UserID,
+		Username: key.Username,
+		Scopes:   toScopes(key.Scopes),
+	}, nil
+}
+
+func toResponse(key ApiKey) Response {
+	var lastUsedAt *time.Time
+	if key.LastUsedAt.Valid {
+		lastUsedAt = &key.LastUsedAt.Time
+	}
+
+	return Response{
+		KeyID:      key.ID,
+		Name:       key.Name,
+		Scopes:     toScopes(key.Scopes),
+		LastUsedAt: lastUsedAt,
+		CreatedAt:  key.CreatedAt.Time,
+	}
+}
+
+func toScopes(scopeStrs []string) []auth.Scope {
+	scopes := make([]auth.Scope, len(scopeStrs))
+	for i, scope := range scopeStrs {
+		scopes[i] = auth.Scope(scope)
+	}
+	return scopes
+}
@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"database-final-project/internal/auth"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateRequest struct {
+	Name   string       `json:"name" validate:"required,max=255"`
+	Scopes []auth.Scope `json:"scopes" validate:"required,min=1"`
+}
+
+type Response struct {
+	KeyID      uuid.UUID    `json:"key_id"`
+	Name       string       `json:"name"`
+	Scopes     []auth.Scope `json:"scopes"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// CreatedResponse is returned once when a key is issued. Only a hash of Key
+// is stored, so it cannot be shown again.
+type CreatedResponse struct {
+	Response
+	Key string `json:"key"`
+}
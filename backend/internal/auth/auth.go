@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"database-final-project/internal"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// KeyPrefix starts every API key, so only tokens with it are looked up as keys.
+const KeyPrefix = "fk_"
+
+// Scope is a kind of access an API key can be given.
+type Scope string
+
+const (
+	ScopeFormsRead      Scope = "forms:read"
+	ScopeFormsWrite     Scope = "forms:write"
+	ScopeResponsesRead  Scope = "responses:read"
+	ScopeResponsesWrite Scope = "responses:write"
+)
+
+var Scopes = []Scope{ScopeFormsRead, ScopeFormsWrite, ScopeResponsesRead, ScopeResponsesWrite}
+
+// User is the signed-in user a request is made on behalf of.
+type User struct {
+	ID       uuid.UUID
+	Username string
+	// Scopes limits what a request authenticated with an API key may do. It
+	// is nil for sessions, which may do everything.
+	Scopes []Scope
+}
+
+// HasScope reports whether the request may do what the scope covers.
+func (u User) HasScope(scope Scope) bool {
+	return u.Scopes == nil || slices.Contains(u.Scopes, scope)
+}
+
+// IsAPIKey reports whether the request was authenticated with an API key.
+func (u User) IsAPIKey() bool {
+	return u.Scopes != nil
+}
+
+type userKey struct{}
+
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user the request was authenticated as, if any.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+type authenticator interface {
+	Authenticate(ctx context.Context, token string) (User, error)
+}
+
+// Middleware authenticates requests carrying an "Authorization: Bearer"
+// token and puts the user into the request context. Requests without the
+// header pass through anonymously, so handlers decide which routes need a
+// user; a token that does not check out is rejected with 401. Requests
+// already authenticated by KeyMiddleware pass through as they are.
+func Middleware(next http.HandlerFunc, logger *zap.Logger, authenticator authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFromContext(r.Context()); ok || r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := BearerToken(r)
+		if !ok {
+			internal.WriteResponseToBody(w, logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Authorization header must be a bearer token"))
+			return
+		}
+
+		user, err := authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, ErrInvalidToken) {
+				internal.WriteResponseToBody(w, logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Invalid or expired token"))
+				return
+			}
+			logger.Error("Failed to authenticate request", zap.Error(err))
+			internal.WriteResponseToBody(w, logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to authenticate request"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	}
+}
+
+// KeyMiddleware authenticates requests whose bearer token is an API key and
+// puts the user of the key into the request context. Every other request is
+// left to Middleware, including tokens with the key prefix that are not a
+// key, since session tokens are random and may start with it too.
+func KeyMiddleware(next http.HandlerFunc, logger *zap.Logger, authenticator authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := BearerToken(r)
+		if !ok || !strings.HasPrefix(token, KeyPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, ErrInvalidToken) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			logger.Error("Failed to authenticate request", zap.Error(err))
+			internal.WriteResponseToBody(w, logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to authenticate request"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	}
+}
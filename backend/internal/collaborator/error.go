@@ -0,0 +1,10 @@
+package collaborator
+
+import "errors"
+
+var (
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidRole          = errors.New("role must be viewer, editor or owner")
+	ErrFormOwner            = errors.New("the owner of the form already has full access")
+)
@@ -0,0 +1,26 @@
+-- name: GetByFormID :many
+SELECT c.user_id, u.username, c.role, c.created_at, c.updated_at
+FROM form_collaborators c
+         JOIN users u ON u.id = c.user_id
+WHERE c.form_id = $1
+ORDER BY c.created_at, u.username;
+
+-- name: GetRole :one
+SELECT role FROM form_collaborators WHERE form_id = $1 AND user_id = $2;
+
+-- name: GetUserByUsername :one
+SELECT id, username FROM users WHERE username = $1;
+
+-- name: IsFormOwner :one
+SELECT EXISTS (SELECT 1 FROM forms WHERE id = $1 AND owner_id = $2);
+
+-- name: Upsert :one
+INSERT INTO form_collaborators (form_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (form_id, user_id) DO UPDATE
+    SET role       = EXCLUDED.role,
+        updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM form_collaborators WHERE form_id = $1 AND user_id = $2;
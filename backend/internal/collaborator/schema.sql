@@ -0,0 +1,13 @@
+CREATE TYPE collaborator_role AS ENUM ('viewer', 'editor', 'owner');
+
+CREATE TABLE IF NOT EXISTS form_collaborators
+(
+    form_id    UUID              NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    user_id    UUID              NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       collaborator_role NOT NULL,
+    created_at TIMESTAMPTZ       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (form_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS form_collaborators_user_id_idx ON form_collaborators (user_id);
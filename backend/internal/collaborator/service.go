@@ -0,0 +1,128 @@
+package collaborator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Querier interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]GetByFormIDRow, error)
+	GetRole(ctx context.Context, arg GetRoleParams) (CollaboratorRole, error)
+	GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error)
+	IsFormOwner(ctx context.Context, arg IsFormOwnerParams) (bool, error)
+	Upsert(ctx context.Context, arg UpsertParams) (FormCollaborator, error)
+	Delete(ctx context.Context, arg DeleteParams) (int64, error)
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (s *Service) GetByFormID(ctx context.Context, formID uuid.UUID) ([]Response, error) {
+	rows, err := s.queries.GetByFormID(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators := make([]Response, 0, len(rows))
+	for _, row := range rows {
+		collaborators = append(collaborators, Response{
+			UserID:    row.UserID,
+			Username:  row.Username,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt.Time,
+			UpdatedAt: row.UpdatedAt.Time,
+		})
+	}
+
+	return collaborators, nil
+}
+
+// GetRole returns the role the user was granted on the form, or
+// ErrCollaboratorNotFound.
+func (s *Service) GetRole(ctx context.Context, formID uuid.UUID, userID uuid.UUID) (Role, error) {
+	role, err := s.queries.GetRole(ctx, GetRoleParams{
+		FormID: formID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCollaboratorNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// Grant gives the user with the username the role on the form, replacing any
+// role they had before.
+func (s *Service) Grant(ctx context.Context, formID uuid.UUID, username string, role Role) (Response, error) {
+	if !role.Valid() {
+		return Response{}, ErrInvalidRole
+	}
+
+	user, err := s.queries.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Response{}, ErrUserNotFound
+		}
+		return Response{}, err
+	}
+
+	isOwner, err := s.queries.IsFormOwner(ctx, IsFormOwnerParams{
+		ID:      formID,
+		OwnerID: pgtype.UUID{Bytes: user.ID, Valid: true},
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	if isOwner {
+		return Response{}, ErrFormOwner
+	}
+
+	collaborator, err := s.queries.Upsert(ctx, UpsertParams{
+		FormID: formID,
+		UserID: user.ID,
+		Role:   role,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		UserID:    collaborator.UserID,
+		Username:  user.Username,
+		Role:      collaborator.Role,
+		CreatedAt: collaborator.CreatedAt.Time,
+		UpdatedAt: collaborator.UpdatedAt.Time,
+	}, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, formID uuid.UUID, userID uuid.UUID) error {
+	deleted, err := s.queries.Delete(ctx, DeleteParams{
+		FormID: formID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrCollaboratorNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package collaborator
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is what a user may do with a form. Each role includes everything the
+// roles before it allow: viewers read results, editors also edit the form and
+// its answers, owners also delete the form and manage collaborators.
+type Role = CollaboratorRole
+
+const (
+	RoleViewer = CollaboratorRoleViewer
+	RoleEditor = CollaboratorRoleEditor
+	RoleOwner  = CollaboratorRoleOwner
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Allows reports whether the role grants what the required role does.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[required]
+}
+
+type Request struct {
+	Username string `json:"username" validate:"required"`
+	Role     Role   `json:"role" validate:"required"`
+}
+
+type Response struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
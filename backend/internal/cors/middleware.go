@@ -28,7 +28,8 @@ func CORSMiddleware(next http.HandlerFunc, logger *zap.Logger, allowOrigin []str
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turns a page position into an opaque string for clients.
+func EncodeCursor(position interface{}) string {
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a string produced by EncodeCursor back into position.
+func DecodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	err = json.Unmarshal(data, position)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
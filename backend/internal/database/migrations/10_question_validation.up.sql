@@ -0,0 +1,2 @@
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS validation JSONB NOT NULL DEFAULT '{}';
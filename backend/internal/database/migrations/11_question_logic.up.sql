@@ -0,0 +1,2 @@
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS logic JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+ALTER TABLE questions DROP COLUMN IF EXISTS section_id;
+
+DROP TABLE IF EXISTS sections;
@@ -0,0 +1,3 @@
+ALTER TABLE questions DROP COLUMN IF EXISTS description;
+
+ALTER TABLE forms DROP COLUMN IF EXISTS description;
@@ -0,0 +1,5 @@
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
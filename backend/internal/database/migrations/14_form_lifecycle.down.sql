@@ -0,0 +1,8 @@
+ALTER TABLE forms
+    DROP CONSTRAINT IF EXISTS forms_schedule_check,
+    DROP COLUMN IF EXISTS max_responses,
+    DROP COLUMN IF EXISTS closes_at,
+    DROP COLUMN IF EXISTS opens_at,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS form_status;
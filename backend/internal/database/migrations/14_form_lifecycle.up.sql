@@ -0,0 +1,9 @@
+CREATE TYPE form_status AS ENUM ('draft', 'open', 'closed');
+
+-- Existing forms keep accepting submissions.
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS status        form_status NOT NULL DEFAULT 'open',
+    ADD COLUMN IF NOT EXISTS opens_at      TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS closes_at     TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS max_responses INTEGER CHECK (max_responses > 0),
+    ADD CONSTRAINT forms_schedule_check CHECK (closes_at > opens_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS forms_owner_id_idx;
+
+ALTER TABLE forms DROP COLUMN IF EXISTS owner_id;
+
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
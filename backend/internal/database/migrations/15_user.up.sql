@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    username      VARCHAR(64) NOT NULL UNIQUE,
+    password_hash TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash BYTEA       NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+
+-- Forms created before accounts existed have no owner, and neither do forms
+-- whose owner was deleted: their questions and responses are kept. They can
+-- still be filled in, but nobody may read, edit or delete them until an owner
+-- is backfilled, e.g. UPDATE forms SET owner_id = '<user id>' WHERE owner_id IS NULL;
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS forms_owner_id_idx ON forms (owner_id);
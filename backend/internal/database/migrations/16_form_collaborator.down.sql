@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS form_collaborators;
+DROP TYPE IF EXISTS collaborator_role;
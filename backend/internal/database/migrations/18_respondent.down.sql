@@ -0,0 +1,12 @@
+ALTER TABLE submissions
+    DROP CONSTRAINT IF EXISTS submissions_form_id_respondent_key_key,
+    DROP COLUMN IF EXISTS respondent_key,
+    DROP COLUMN IF EXISTS respondent_email,
+    DROP COLUMN IF EXISTS respondent_id;
+
+ALTER TABLE forms
+    DROP CONSTRAINT IF EXISTS forms_one_response_check,
+    DROP COLUMN IF EXISTS one_response_per_respondent,
+    DROP COLUMN IF EXISTS respondent_identity;
+
+DROP TYPE IF EXISTS respondent_identity;
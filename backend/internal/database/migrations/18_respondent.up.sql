@@ -0,0 +1,14 @@
+CREATE TYPE respondent_identity AS ENUM ('anonymous', 'optional', 'required');
+
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS respondent_identity respondent_identity NOT NULL DEFAULT 'anonymous',
+    ADD COLUMN IF NOT EXISTS one_response_per_respondent BOOLEAN NOT NULL DEFAULT false,
+    ADD CONSTRAINT forms_one_response_check CHECK (NOT one_response_per_respondent OR respondent_identity = 'required');
+
+-- respondent_key is only set for forms limited to one response per
+-- respondent, and identifies the respondent for the unique constraint.
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS respondent_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS respondent_email VARCHAR(320),
+    ADD COLUMN IF NOT EXISTS respondent_key TEXT,
+    ADD CONSTRAINT submissions_form_id_respondent_key_key UNIQUE (form_id, respondent_key);
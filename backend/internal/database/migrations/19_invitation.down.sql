@@ -0,0 +1,7 @@
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS invitation_id;
+
+ALTER TABLE forms
+    DROP COLUMN IF EXISTS invite_only;
+
+DROP TABLE IF EXISTS form_invitations;
@@ -0,0 +1,4 @@
+ALTER TABLE submissions
+    DROP CONSTRAINT IF EXISTS submissions_form_id_idempotency_key_key,
+    DROP COLUMN IF EXISTS request_hash,
+    DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,6 @@
+-- A retried submission carries the idempotency_key of the first attempt;
+-- request_hash tells a retry apart from a different request reusing the key.
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS request_hash BYTEA,
+    ADD CONSTRAINT submissions_form_id_idempotency_key_key UNIQUE (form_id, idempotency_key);
@@ -0,0 +1,2 @@
+ALTER TABLE options DROP COLUMN IF EXISTS position;
+ALTER TABLE questions DROP COLUMN IF EXISTS position;
@@ -0,0 +1,17 @@
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE options
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE questions q
+SET position = ordered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY form_id ORDER BY created_at, id) - 1 AS position
+      FROM questions) ordered
+WHERE q.id = ordered.id;
+
+UPDATE options o
+SET position = ordered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY question_id ORDER BY created_at, id) - 1 AS position
+      FROM options) ordered
+WHERE o.id = ordered.id;
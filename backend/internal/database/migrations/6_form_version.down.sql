@@ -0,0 +1,10 @@
+DELETE
+FROM answers a
+WHERE NOT EXISTS (SELECT 1 FROM questions q WHERE q.id = a.question_id);
+
+ALTER TABLE answers
+    ADD CONSTRAINT answers_question_id_fkey FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE;
+
+ALTER TABLE submissions DROP COLUMN IF EXISTS form_version_id;
+
+DROP TABLE IF EXISTS form_versions;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS form_versions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    version    INTEGER     NOT NULL,
+    definition JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (form_id, version)
+);
+
+-- Snapshot the current question tree of every existing form as its first version.
+INSERT INTO form_versions (form_id, version, definition)
+SELECT f.id,
+       1,
+       COALESCE((SELECT jsonb_agg(jsonb_build_object(
+                                          'question_id', q.id,
+                                          'type', q.type,
+                                          'question_text', q.text,
+                                          'is_required', q.is_required,
+                                          'position', q.position,
+                                          'options', (SELECT jsonb_agg(jsonb_build_object(
+                                                                               'option_id', o.id,
+                                                                               'option_text', o.text,
+                                                                               'position', o.position
+                                                                       ) ORDER BY o.position, o.created_at)
+                                                      FROM options o
+                                                      WHERE o.question_id = q.id)
+                                  ) ORDER BY q.position, q.created_at)
+                 FROM questions q
+                 WHERE q.form_id = f.id), '[]'::jsonb)
+FROM forms f;
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS form_version_id UUID REFERENCES form_versions (id) ON DELETE CASCADE;
+
+UPDATE submissions s
+SET form_version_id = v.id
+FROM form_versions v
+WHERE v.form_id = s.form_id;
+
+ALTER TABLE submissions
+    ALTER COLUMN form_version_id SET NOT NULL;
+
+-- Answers are rendered against their version, so they must outlive edited questions.
+ALTER TABLE answers
+    DROP CONSTRAINT IF EXISTS answers_question_id_fkey;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS forms_title_id_idx;
+DROP INDEX IF EXISTS forms_updated_at_id_idx;
+DROP INDEX IF EXISTS forms_created_at_id_idx;
+
+ALTER TABLE forms
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN updated_at DROP NOT NULL;
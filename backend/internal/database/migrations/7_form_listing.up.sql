@@ -0,0 +1,10 @@
+UPDATE forms SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE forms SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE forms
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS forms_created_at_id_idx ON forms (created_at, id);
+CREATE INDEX IF NOT EXISTS forms_updated_at_id_idx ON forms (updated_at, id);
+CREATE INDEX IF NOT EXISTS forms_title_id_idx ON forms (title, id);
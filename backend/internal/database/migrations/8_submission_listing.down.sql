@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS answers_submission_id_idx;
+DROP INDEX IF EXISTS submissions_form_id_created_at_id_idx;
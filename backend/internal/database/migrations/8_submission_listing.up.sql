@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS submissions_form_id_created_at_id_idx ON submissions (form_id, created_at, id);
+CREATE INDEX IF NOT EXISTS answers_submission_id_idx ON answers (submission_id);
@@ -0,0 +1,18 @@
+ALTER TABLE answers
+    DROP COLUMN IF EXISTS answer_time,
+    DROP COLUMN IF EXISTS answer_date,
+    DROP COLUMN IF EXISTS answer_number;
+
+ALTER TABLE questions DROP COLUMN IF EXISTS config;
+
+-- Enum values cannot be dropped, so the type is recreated. Questions of the
+-- new types fall back to short_answer and keep their text answers.
+UPDATE questions
+SET type = 'short_answer'
+WHERE type::text NOT IN ('short_answer', 'select', 'multiselect');
+
+ALTER TYPE question_type RENAME TO question_type_old;
+CREATE TYPE question_type AS ENUM ('short_answer', 'select', 'multiselect');
+ALTER TABLE questions
+    ALTER COLUMN type TYPE question_type USING type::text::question_type;
+DROP TYPE question_type_old;
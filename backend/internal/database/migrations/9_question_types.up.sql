@@ -0,0 +1,16 @@
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'paragraph';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'number';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'email';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'url';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'date';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'time';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'rating';
+ALTER TYPE question_type ADD VALUE IF NOT EXISTS 'linear_scale';
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS config JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE answers
+    ADD COLUMN IF NOT EXISTS answer_number DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS answer_date   DATE,
+    ADD COLUMN IF NOT EXISTS answer_time   TIME;
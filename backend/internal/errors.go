@@ -1,10 +1,16 @@
 package internal
 
 type ErrorResponse struct {
-	Title   string `json:"title"`
-	Status  int    `json:"status"`
+	Title   string       `json:"title"`
+	Status  int          `json:"status"`
+	Message string       `json:"message"`
+	Type    string       `json:"type"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Type    string `json:"type"`
 }
 
 func NewBadRequestError(message string) *ErrorResponse {
@@ -16,6 +22,15 @@ func NewBadRequestError(message string) *ErrorResponse {
 	}
 }
 
+func NewUnauthorizedError(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Title:   "Unauthorized",
+		Status:  401,
+		Message: message,
+		Type:    "https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Status/401",
+	}
+}
+
 func NewNotFoundError(message string) *ErrorResponse {
 	return &ErrorResponse{
 		Title:   "Not Found",
@@ -34,6 +49,25 @@ func NewForbiddenError(message string) *ErrorResponse {
 	}
 }
 
+func NewConflictError(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Title:   "Conflict",
+		Status:  409,
+		Message: message,
+		Type:    "https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Status/409",
+	}
+}
+
+func NewUnprocessableEntityError(message string, errors []FieldError) *ErrorResponse {
+	return &ErrorResponse{
+		Title:   "Unprocessable Entity",
+		Status:  422,
+		Message: message,
+		Type:    "https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Status/422",
+		Errors:  errors,
+	}
+}
+
 func NewInternalServerError(message string) *ErrorResponse {
 	return &ErrorResponse{
 		Title:   "Internal Server Error",
@@ -0,0 +1,7 @@
+package export
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat = errors.New("format must be csv, xlsx or jsonl")
+)
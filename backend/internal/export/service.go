@@ -0,0 +1,167 @@
+package export
+
+import (
+	"context"
+	"database-final-project/internal/answer"
+	"database-final-project/internal/submission"
+	"database-final-project/internal/version"
+	"io"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// batchSize is the number of submissions loaded per round trip while exporting.
+const batchSize = 100
+
+type submissionStore interface {
+	ListBatch(ctx context.Context, formID uuid.UUID, params submission.ListParams) (submission.ListResponse, error)
+}
+
+type versionStore interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]version.Response, error)
+}
+
+// flusher is implemented by http.ResponseWriter.
+type flusher interface {
+	Flush()
+}
+
+type Service struct {
+	logger          *zap.Logger
+	submissionStore submissionStore
+	versionStore    versionStore
+}
+
+func NewService(logger *zap.Logger, submissionStore submissionStore, versionStore versionStore) *Service {
+	return &Service{
+		logger:          logger,
+		submissionStore: submissionStore,
+		versionStore:    versionStore,
+	}
+}
+
+// Export writes the form's submissions matching params to w, one row per
+// submission. Submissions are loaded and written in batches, so only one batch
+// is held in memory at a time. Limit and Cursor of params are ignored.
+func (s *Service) Export(ctx context.Context, w io.Writer, formID uuid.UUID, format Format, params submission.ListParams) error {
+	writer, err := newRowWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	versions, err := s.versionStore.GetByFormID(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	columns := buildColumns(versions)
+	columnIndex := make(map[uuid.UUID]int, len(columns))
+	for i, column := range columns {
+		columnIndex[column.QuestionID] = i
+	}
+
+	err = writer.WriteHeader(columns)
+	if err != nil {
+		return err
+	}
+
+	params.Limit = batchSize
+	params.Cursor = ""
+	for {
+		page, err := s.submissionStore.ListBatch(ctx, formID, params)
+		if err != nil {
+			return err
+		}
+
+		for _, answersSubmission := range page.Submissions {
+			err = writer.WriteRow(toRow(answersSubmission, columnIndex, len(columns)))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writer.Flush()
+		if err != nil {
+			return err
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+
+	return writer.Close()
+}
+
+// buildColumns lists the questions of the latest version in form order,
+// followed by questions that only exist in older versions, newest first.
+func buildColumns(versions []version.Response) []Column {
+	var columns []Column
+	seen := make(map[uuid.UUID]bool)
+	for i := len(versions) - 1; i >= 0; i-- {
+		for _, q := range versions[i].Questions {
+			if seen[q.QuestionID] {
+				continue
+			}
+			seen[q.QuestionID] = true
+			columns = append(columns, Column{
+				QuestionID:   q.QuestionID,
+				QuestionText: q.QuestionText,
+			})
+		}
+	}
+
+	return columns
+}
+
+func toRow(answersSubmission submission.AnswersSubmission, columnIndex map[uuid.UUID]int, columnCount int) Row {
+	row := Row{
+		SubmissionID: answersSubmission.SubmissionID,
+		FormVersion:  answersSubmission.FormVersion,
+		CreatedAt:    answersSubmission.CreatedAt,
+		UpdatedAt:    answersSubmission.UpdatedAt,
+		Values:       make([][]string, columnCount),
+	}
+
+	for _, a := range answersSubmission.Answers {
+		i, ok := columnIndex[a.QuestionID]
+		if !ok {
+			continue
+		}
+		row.Values[i] = values(a)
+	}
+
+	return row
+}
+
+// values resolves the answer to text, replacing option IDs by the option text
+// of the version the submission was answered against.
+func values(a answer.Response) []string {
+	if len(a.AnswerOptions) == 0 {
+		if a.AnswerText == "" {
+			return nil
+		}
+		return []string{a.AnswerText}
+	}
+
+	optionTexts := make(map[uuid.UUID]string, len(a.Options))
+	for _, option := range a.Options {
+		optionTexts[option.OptionID] = option.OptionText
+	}
+
+	values := make([]string, len(a.AnswerOptions))
+	for i, optionID := range a.AnswerOptions {
+		text, ok := optionTexts[optionID]
+		if !ok {
+			text = optionID.String()
+		}
+		values[i] = text
+	}
+
+	return values
+}
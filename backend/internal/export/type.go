@@ -0,0 +1,44 @@
+package export
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatXLSX  Format = "xlsx"
+	FormatJSONL Format = "jsonl"
+)
+
+// ContentType is the media type of the exported document.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSONL:
+		return "application/jsonl"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Column is one question of the form. Questions that were removed from the
+// form still get a column so that older submissions keep their answers.
+type Column struct {
+	QuestionID   uuid.UUID
+	QuestionText string
+}
+
+// Row is one submission. Values holds, per column, the answer text or the
+// texts of the selected options; it is empty when the question was not answered.
+type Row struct {
+	SubmissionID uuid.UUID
+	FormVersion  int32
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Values       [][]string
+}
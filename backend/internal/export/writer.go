@@ -0,0 +1,137 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// multiValueSeparator joins the selected options of a multiselect answer in
+// flat formats.
+const multiValueSeparator = "; "
+
+// fixedHeader are the submission columns written before the question columns.
+var fixedHeader = []string{"submission_id", "form_version", "created_at", "updated_at"}
+
+// rowWriter encodes rows into one export format. Flush pushes buffered rows to
+// the underlying writer; Close finishes the document.
+type rowWriter interface {
+	WriteHeader(columns []Column) error
+	WriteRow(row Row) error
+	Flush() error
+	Close() error
+}
+
+func newRowWriter(w io.Writer, format Format) (rowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func header(columns []Column) []string {
+	record := append([]string{}, fixedHeader...)
+	for _, column := range columns {
+		record = append(record, column.QuestionText)
+	}
+	return record
+}
+
+func record(row Row) []string {
+	record := []string{
+		row.SubmissionID.String(),
+		strconv.Itoa(int(row.FormVersion)),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, values := range row.Values {
+		record = append(record, strings.Join(values, multiValueSeparator))
+	}
+	return record
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(columns []Column) error {
+	return c.w.Write(header(columns))
+}
+
+func (c *csvWriter) WriteRow(row Row) error {
+	return c.w.Write(record(row))
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+// jsonlWriter writes one JSON object per submission. Unanswered questions are
+// left out and multiselect answers stay arrays.
+type jsonlWriter struct {
+	encoder *json.Encoder
+	columns []Column
+}
+
+type jsonlRow struct {
+	SubmissionID string        `json:"submission_id"`
+	FormVersion  int32         `json:"form_version"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Answers      []jsonlAnswer `json:"answers"`
+}
+
+type jsonlAnswer struct {
+	QuestionID   string   `json:"question_id"`
+	QuestionText string   `json:"question_text"`
+	Values       []string `json:"values"`
+}
+
+func (j *jsonlWriter) WriteHeader(columns []Column) error {
+	j.columns = columns
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(row Row) error {
+	answers := []jsonlAnswer{}
+	for i, values := range row.Values {
+		if len(values) == 0 {
+			continue
+		}
+		answers = append(answers, jsonlAnswer{
+			QuestionID:   j.columns[i].QuestionID.String(),
+			QuestionText: j.columns[i].QuestionText,
+			Values:       values,
+		})
+	}
+
+	return j.encoder.Encode(jsonlRow{
+		SubmissionID: row.SubmissionID.String(),
+		FormVersion:  row.FormVersion,
+		CreatedAt:    row.CreatedAt.UTC(),
+		UpdatedAt:    row.UpdatedAt.UTC(),
+		Answers:      answers,
+	})
+}
+
+func (j *jsonlWriter) Flush() error {
+	return nil
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
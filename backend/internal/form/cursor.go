@@ -0,0 +1,26 @@
+package form
+
+import (
+	"database-final-project/internal"
+
+	"github.com/google/uuid"
+)
+
+// cursor marks the last form of a page. It is handed to clients as an opaque
+// string and only valid for the sort it was created with.
+type cursor struct {
+	Sort       string    `json:"s"`
+	Descending bool      `json:"d"`
+	Value      string    `json:"v"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func (c cursor) encode() string {
+	return internal.EncodeCursor(c)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	err := internal.DecodeCursor(s, &c)
+	return c, err
+}
@@ -0,0 +1,293 @@
+package form
+
+import (
+	"bytes"
+	"database-final-project/internal/options"
+	"database-final-project/internal/question"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// DefinitionVersion is the version of the definition document format.
+const DefinitionVersion = 1
+
+// Definition is a portable description of a form without any IDs, so that it
+// can be kept in git and imported into another environment. The questions are
+// listed either directly or grouped by section.
+type Definition struct {
+	Version     int                  `json:"version" yaml:"version"`
+	Title       string               `json:"title" yaml:"title"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Questions   []DefinitionQuestion `json:"questions,omitempty" yaml:"questions,omitempty"`
+	Sections    []DefinitionSection  `json:"sections,omitempty" yaml:"sections,omitempty"`
+}
+
+type DefinitionSection struct {
+	Title       string               `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Questions   []DefinitionQuestion `json:"questions" yaml:"questions"`
+}
+
+type DefinitionQuestion struct {
+	Type        QuestionType         `json:"type" yaml:"type"`
+	Text        string               `json:"text" yaml:"text"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool                 `json:"required" yaml:"required"`
+	Config      *question.Config     `json:"config,omitempty" yaml:"config,omitempty"`
+	Validation  *question.Validation `json:"validation,omitempty" yaml:"validation,omitempty"`
+	// Logic refers to questions and options by their index in the definition,
+	// counting across sections.
+	Logic   *question.Logic `json:"logic,omitempty" yaml:"logic,omitempty"`
+	Options []string        `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+func toDefinition(form QuestionsForm) Definition {
+	definition := Definition{
+		Version:     DefinitionVersion,
+		Title:       form.Title,
+		Description: form.Description,
+	}
+
+	toQuestions := func(questions []question.OptionsQuestion) []DefinitionQuestion {
+		definitionQuestions := make([]DefinitionQuestion, len(questions))
+		for i, q := range questions {
+			definitionQuestion := DefinitionQuestion{
+				Type:        QuestionType(q.QuestionType),
+				Text:        q.QuestionText,
+				Description: q.Description,
+				Required:    q.IsRequired,
+				Config:      q.Config,
+				Validation:  q.Validation,
+				Logic:       question.IndexLogic(q.Logic, form.Questions, form.sectionIDs()),
+			}
+			for _, option := range q.Options {
+				definitionQuestion.Options = append(definitionQuestion.Options, option.OptionText)
+			}
+			definitionQuestions[i] = definitionQuestion
+		}
+		return definitionQuestions
+	}
+
+	if len(form.Sections) == 0 {
+		definition.Questions = toQuestions(form.Questions)
+		return definition
+	}
+
+	definition.Sections = make([]DefinitionSection, len(form.Sections))
+	for i, s := range form.Sections {
+		definition.Sections[i] = DefinitionSection{
+			Title:       s.Title,
+			Description: s.Description,
+			Questions:   toQuestions(s.Questions),
+		}
+	}
+
+	return definition
+}
+
+// isYAML reports whether the media type names a YAML document.
+func isYAML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseDefinition decodes a JSON or YAML definition, rejecting unknown fields.
+func parseDefinition(body []byte, yamlDocument bool) (Definition, error) {
+	var definition Definition
+	if yamlDocument {
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		err := decoder.Decode(&definition)
+		if err != nil {
+			return Definition{}, err
+		}
+		return definition, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&definition)
+	if err != nil {
+		return Definition{}, err
+	}
+
+	return definition, nil
+}
+
+// validate checks the definition against the rules the create endpoint relies
+// on and reports every problem as a *DefinitionError.
+func (d Definition) validate() error {
+	validationErr := &DefinitionError{}
+
+	if d.Version != 0 && d.Version != DefinitionVersion {
+		validationErr.add("version", fmt.Sprintf("unsupported version, expected %d", DefinitionVersion))
+	}
+
+	title := strings.TrimSpace(d.Title)
+	if title == "" || utf8.RuneCountInString(title) > 255 {
+		validationErr.add("title", "title must be between 1 and 255 characters")
+	}
+	if utf8.RuneCountInString(d.Description) > maxDescriptionLength {
+		validationErr.add("description", ErrDescriptionTooLong.Error())
+	}
+
+	if len(d.Questions) > 0 && len(d.Sections) > 0 {
+		validationErr.add("sections", ErrQuestionsAndSections.Error())
+	}
+	for i, s := range d.Sections {
+		if utf8.RuneCountInString(s.Title) > 255 {
+			validationErr.add(fmt.Sprintf("sections[%d].title", i), ErrSectionTitleTooLong.Error())
+		}
+	}
+
+	questions, fields := d.allQuestions()
+	for i, q := range questions {
+		field := fields[i]
+
+		text := strings.TrimSpace(q.Text)
+		if text == "" || utf8.RuneCountInString(text) > 1000 {
+			validationErr.add(field+".text", "text must be between 1 and 1000 characters")
+		}
+		if utf8.RuneCountInString(q.Description) > question.MaxDescriptionLength {
+			validationErr.add(field+".description", question.ErrDescriptionTooLong.Error())
+		}
+
+		_, err := question.NormalizeConfig(string(q.Type), q.Config)
+		if errors.Is(err, question.ErrInvalidQuestionType) {
+			validationErr.add(field+".type", err.Error())
+			continue
+		}
+		if err != nil {
+			validationErr.add(field+".config", err.Error())
+		}
+
+		_, err = question.NormalizeValidation(string(q.Type), q.Validation)
+		if err != nil {
+			validationErr.add(field+".validation", err.Error())
+		}
+
+		if question.HasOptions(string(q.Type)) && len(q.Options) == 0 {
+			validationErr.add(field+".options", fmt.Sprintf("%s questions need at least one option", q.Type))
+		}
+		if !question.HasOptions(string(q.Type)) && len(q.Options) > 0 {
+			validationErr.add(field+".options", fmt.Sprintf("%s questions cannot have options", q.Type))
+		}
+
+		seen := make(map[string]bool, len(q.Options))
+		for j, option := range q.Options {
+			optionField := fmt.Sprintf("%s.options[%d]", field, j)
+			if strings.TrimSpace(option) == "" {
+				validationErr.add(optionField, "option cannot be empty")
+				continue
+			}
+			if seen[option] {
+				validationErr.add(optionField, "option is listed more than once")
+			}
+			seen[option] = true
+		}
+	}
+
+	// Logic can only use indices here, so it is checked against stand-ins for
+	// the questions and options the definition will create.
+	placeholders := make([]question.OptionsQuestion, len(questions))
+	for i, q := range questions {
+		placeholders[i] = question.OptionsQuestion{
+			QuestionID:   uuid.New(),
+			QuestionType: question.QuestionType(q.Type),
+			Options:      make([]options.Response, len(q.Options)),
+		}
+		for j := range q.Options {
+			placeholders[i].Options[j].OptionID = uuid.New()
+		}
+	}
+	sectionIDs := make([]uuid.UUID, len(d.Sections))
+	next := len(d.Questions)
+	for i, s := range d.Sections {
+		sectionIDs[i] = uuid.New()
+		for range s.Questions {
+			placeholders[next].SectionID = &sectionIDs[i]
+			next++
+		}
+	}
+	for i, q := range questions {
+		_, err := question.ResolveLogic(q.Logic, placeholders, sectionIDs, i)
+		if err != nil {
+			validationErr.add(fields[i]+".logic", err.Error())
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+
+	return nil
+}
+
+// allQuestions lists the questions of the definition in form order together
+// with the field each one is reported under.
+func (d Definition) allQuestions() ([]DefinitionQuestion, []string) {
+	var questions []DefinitionQuestion
+	var fields []string
+	for i, q := range d.Questions {
+		questions = append(questions, q)
+		fields = append(fields, fmt.Sprintf("questions[%d]", i))
+	}
+	for i, s := range d.Sections {
+		for j, q := range s.Questions {
+			questions = append(questions, q)
+			fields = append(fields, fmt.Sprintf("sections[%d].questions[%d]", i, j))
+		}
+	}
+	return questions, fields
+}
+
+// requests converts the definition into the questions or sections of a
+// create request.
+func (d Definition) requests() ([]QuestionRequest, []SectionRequest) {
+	toRequests := func(questions []DefinitionQuestion) []QuestionRequest {
+		questionRequests := make([]QuestionRequest, len(questions))
+		for i, q := range questions {
+			questionRequests[i] = QuestionRequest{
+				QuestionType: q.Type,
+				IsRequired:   q.Required,
+				QuestionText: q.Text,
+				Description:  q.Description,
+				Config:       q.Config,
+				Validation:   q.Validation,
+				Logic:        q.Logic,
+				Options:      q.Options,
+			}
+		}
+		return questionRequests
+	}
+
+	if len(d.Sections) == 0 {
+		return toRequests(d.Questions), nil
+	}
+
+	sectionRequests := make([]SectionRequest, len(d.Sections))
+	for i, s := range d.Sections {
+		sectionRequests[i] = SectionRequest{
+			Title:       s.Title,
+			Description: s.Description,
+			Questions:   toRequests(s.Questions),
+		}
+	}
+	return nil, sectionRequests
+}
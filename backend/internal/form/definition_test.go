@@ -0,0 +1,378 @@
+package form
+
+import (
+	"database-final-project/internal/options"
+	"database-final-project/internal/question"
+	"database-final-project/internal/section"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// definitionForm builds a form with config, validation and logic on its
+// questions, split into two sections when sectioned is set. The first
+// question of a sectioned form jumps to the second section.
+func definitionForm(sectioned bool) QuestionsForm {
+	red := options.Response{OptionID: uuid.New(), OptionText: "Red", Position: 0}
+	green := options.Response{OptionID: uuid.New(), OptionText: "Green", Position: 1}
+
+	questions := []question.OptionsQuestion{
+		{
+			QuestionID:   uuid.New(),
+			QuestionType: question.QuestionTypeSelect,
+			QuestionText: "Favorite color",
+			Description:  "Pick *one*",
+			IsRequired:   true,
+			Options:      []options.Response{red, green},
+		},
+		{
+			QuestionID:   uuid.New(),
+			QuestionType: question.QuestionTypeShortAnswer,
+			QuestionText: "Why green?",
+			Validation:   &question.Validation{MinLength: ptr(3), Pattern: "^[a-z ]+$", PatternMessage: "Lowercase only"},
+		},
+		{
+			QuestionID:   uuid.New(),
+			QuestionType: question.QuestionTypeLinearScale,
+			QuestionText: "How sure are you?",
+			Config:       &question.Config{Min: ptr(1.0), Max: ptr(5.0), MinLabel: "Not at all", MaxLabel: "Very"},
+		},
+		{
+			QuestionID:   uuid.New(),
+			QuestionType: question.QuestionTypeNumber,
+			QuestionText: "How many?",
+			Config:       &question.Config{Min: ptr(0.0), Step: ptr(0.5)},
+		},
+	}
+	questions[1].Logic = &question.Logic{
+		ShowIf: []question.Condition{{QuestionID: &questions[0].QuestionID, Operator: question.OperatorSelected, OptionID: &green.OptionID}},
+	}
+	questions[2].Logic = &question.Logic{
+		Jumps: []question.Jump{
+			{If: question.Condition{QuestionID: &questions[2].QuestionID, Operator: question.OperatorEquals, Value: "5"}, ToQuestionID: &questions[3].QuestionID},
+			{If: question.Condition{QuestionID: &questions[0].QuestionID, Operator: question.OperatorNotSelected, OptionID: &green.OptionID}, End: true},
+		},
+	}
+	for i := range questions {
+		questions[i].Position = int32(i)
+	}
+
+	form := Form{ID: uuid.New(), Title: "Colors", Description: "A *short* survey"}
+	if !sectioned {
+		return toQuestionsForm(form, questions, nil)
+	}
+
+	sections := []section.Response{
+		{SectionID: uuid.New(), Title: "Color", Description: "First page", Position: 0},
+		{SectionID: uuid.New(), Title: "Certainty", Position: 1},
+	}
+	for i := range questions {
+		questions[i].SectionID = &sections[i/2].SectionID
+	}
+	questions[0].Logic = &question.Logic{
+		Jumps: []question.Jump{
+			{If: question.Condition{QuestionID: &questions[0].QuestionID, Operator: question.OperatorSelected, OptionID: &red.OptionID}, ToSectionID: &sections[1].SectionID},
+		},
+	}
+	return toQuestionsForm(form, questions, sections)
+}
+
+// definitionQuestions is what definitionForm exports, with logic pointing at
+// questions and options by index.
+func definitionQuestions() []DefinitionQuestion {
+	return []DefinitionQuestion{
+		{
+			Type:        QuestionTypeSelect,
+			Text:        "Favorite color",
+			Description: "Pick *one*",
+			Required:    true,
+			Options:     []string{"Red", "Green"},
+		},
+		{
+			Type:       QuestionTypeShortAnswer,
+			Text:       "Why green?",
+			Validation: &question.Validation{MinLength: ptr(3), Pattern: "^[a-z ]+$", PatternMessage: "Lowercase only"},
+			Logic: &question.Logic{
+				ShowIf: []question.Condition{{QuestionIndex: ptr(0), Operator: question.OperatorSelected, OptionIndex: ptr(1)}},
+			},
+		},
+		{
+			Type:   QuestionTypeLinearScale,
+			Text:   "How sure are you?",
+			Config: &question.Config{Min: ptr(1.0), Max: ptr(5.0), MinLabel: "Not at all", MaxLabel: "Very"},
+			Logic: &question.Logic{
+				Jumps: []question.Jump{
+					{If: question.Condition{QuestionIndex: ptr(2), Operator: question.OperatorEquals, Value: "5"}, ToQuestionIndex: ptr(3)},
+					{If: question.Condition{QuestionIndex: ptr(0), Operator: question.OperatorNotSelected, OptionIndex: ptr(1)}, End: true},
+				},
+			},
+		},
+		{
+			Type:   QuestionTypeNumber,
+			Text:   "How many?",
+			Config: &question.Config{Min: ptr(0.0), Step: ptr(0.5)},
+		},
+	}
+}
+
+func TestDefinitionRoundTrip(t *testing.T) {
+	questions := definitionQuestions()
+	sectioned := definitionQuestions()
+	sectioned[0].Logic = &question.Logic{
+		Jumps: []question.Jump{
+			{If: question.Condition{QuestionIndex: ptr(0), Operator: question.OperatorSelected, OptionIndex: ptr(0)}, ToSectionIndex: ptr(1)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		sectioned bool
+		want      Definition
+	}{
+		{
+			name: "questions",
+			want: Definition{
+				Version:     DefinitionVersion,
+				Title:       "Colors",
+				Description: "A *short* survey",
+				Questions:   questions,
+			},
+		},
+		{
+			name:      "sections",
+			sectioned: true,
+			want: Definition{
+				Version:     DefinitionVersion,
+				Title:       "Colors",
+				Description: "A *short* survey",
+				Sections: []DefinitionSection{
+					{Title: "Color", Description: "First page", Questions: sectioned[:2]},
+					{Title: "Certainty", Questions: sectioned[2:]},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := toDefinition(definitionForm(tt.sectioned))
+			if !reflect.DeepEqual(definition, tt.want) {
+				t.Fatalf("toDefinition() = %+v, want %+v", definition, tt.want)
+			}
+
+			jsonBody, err := json.Marshal(definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			yamlBody, err := yaml.Marshal(definition)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, document := range []struct {
+				format string
+				body   []byte
+				yaml   bool
+			}{
+				{format: "json", body: jsonBody},
+				{format: "yaml", body: yamlBody, yaml: true},
+			} {
+				parsed, err := parseDefinition(document.body, document.yaml)
+				if err != nil {
+					t.Fatalf("parseDefinition() of %s error = %v", document.format, err)
+				}
+				if !reflect.DeepEqual(parsed, definition) {
+					t.Errorf("%s round trip = %+v, want %+v", document.format, parsed, definition)
+				}
+				if err := parsed.validate(); err != nil {
+					t.Errorf("validate() of %s round trip error = %v", document.format, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDefinitionRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		yaml bool
+	}{
+		{name: "json top level", body: `{"title": "Colors", "colour": "red"}`},
+		{name: "json question", body: `{"title": "Colors", "questions": [{"type": "short_answer", "text": "Name", "hint": "Your name"}]}`},
+		{name: "json section", body: `{"title": "Colors", "sections": [{"title": "Page", "questions": [], "page": 1}]}`},
+		{name: "yaml top level", body: "title: Colors\ncolour: red\n", yaml: true},
+		{name: "yaml question", body: "title: Colors\nquestions:\n  - type: short_answer\n    text: Name\n    hint: Your name\n", yaml: true},
+		{name: "yaml config", body: "title: Colors\nquestions:\n  - type: rating\n    text: Stars\n    config:\n      maximum: 5\n", yaml: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDefinition([]byte(tt.body), tt.yaml)
+			if err == nil {
+				t.Fatal("parseDefinition() error = nil, want an unknown field error")
+			}
+		})
+	}
+}
+
+func TestDefinitionValidateFieldPaths(t *testing.T) {
+	valid := func() DefinitionQuestion {
+		return DefinitionQuestion{Type: QuestionTypeShortAnswer, Text: "Name"}
+	}
+	with := func(change func(q *DefinitionQuestion)) []DefinitionQuestion {
+		q := valid()
+		change(&q)
+		return []DefinitionQuestion{q}
+	}
+
+	tests := []struct {
+		name       string
+		definition Definition
+		want       []string
+	}{
+		{
+			name:       "version",
+			definition: Definition{Version: DefinitionVersion + 1, Title: "Colors"},
+			want:       []string{"version"},
+		},
+		{
+			name:       "title",
+			definition: Definition{Title: "  "},
+			want:       []string{"title"},
+		},
+		{
+			name:       "description",
+			definition: Definition{Title: "Colors", Description: strings.Repeat("a", maxDescriptionLength+1)},
+			want:       []string{"description"},
+		},
+		{
+			name: "questions and sections",
+			definition: Definition{
+				Title:     "Colors",
+				Questions: []DefinitionQuestion{valid()},
+				Sections:  []DefinitionSection{{Questions: []DefinitionQuestion{valid()}}},
+			},
+			want: []string{"sections"},
+		},
+		{
+			name:       "section title",
+			definition: Definition{Title: "Colors", Sections: []DefinitionSection{{Title: strings.Repeat("a", 256)}}},
+			want:       []string{"sections[0].title"},
+		},
+		{
+			name:       "question text",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) { q.Text = "" })},
+			want:       []string{"questions[0].text"},
+		},
+		{
+			name: "question description",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) {
+				q.Description = strings.Repeat("a", question.MaxDescriptionLength+1)
+			})},
+			want: []string{"questions[0].description"},
+		},
+		{
+			name:       "question type",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) { q.Type = "essay" })},
+			want:       []string{"questions[0].type"},
+		},
+		{
+			name:       "question config",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) { q.Config = &question.Config{Max: ptr(5.0)} })},
+			want:       []string{"questions[0].config"},
+		},
+		{
+			name: "question validation",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) {
+				q.Validation = &question.Validation{MinSelections: ptr(1)}
+			})},
+			want: []string{"questions[0].validation"},
+		},
+		{
+			name:       "missing options",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) { q.Type = QuestionTypeSelect })},
+			want:       []string{"questions[0].options"},
+		},
+		{
+			name:       "unexpected options",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) { q.Options = []string{"Red"} })},
+			want:       []string{"questions[0].options"},
+		},
+		{
+			name: "option",
+			definition: Definition{Title: "Colors", Questions: with(func(q *DefinitionQuestion) {
+				q.Type = QuestionTypeSelect
+				q.Options = []string{"Red", " ", "Red"}
+			})},
+			want: []string{"questions[0].options[1]", "questions[0].options[2]"},
+		},
+		{
+			name: "logic",
+			definition: Definition{Title: "Colors", Questions: []DefinitionQuestion{
+				valid(),
+				{Type: QuestionTypeShortAnswer, Text: "Age", Logic: &question.Logic{
+					ShowIf: []question.Condition{{QuestionIndex: ptr(2), Operator: question.OperatorAnswered}},
+				}},
+			}},
+			want: []string{"questions[1].logic"},
+		},
+		{
+			name: "jump to own section",
+			definition: Definition{Title: "Colors", Sections: []DefinitionSection{
+				{Questions: []DefinitionQuestion{{Type: QuestionTypeShortAnswer, Text: "Name", Logic: &question.Logic{
+					Jumps: []question.Jump{{If: question.Condition{QuestionIndex: ptr(0), Operator: question.OperatorAnswered}, ToSectionIndex: ptr(0)}},
+				}}}},
+				{Questions: []DefinitionQuestion{valid()}},
+			}},
+			want: []string{"sections[0].questions[0].logic"},
+		},
+		{
+			name: "jump to empty section",
+			definition: Definition{Title: "Colors", Sections: []DefinitionSection{
+				{Questions: []DefinitionQuestion{{Type: QuestionTypeShortAnswer, Text: "Name", Logic: &question.Logic{
+					Jumps: []question.Jump{{If: question.Condition{QuestionIndex: ptr(0), Operator: question.OperatorAnswered}, ToSectionIndex: ptr(1)}},
+				}}}},
+				{},
+			}},
+			want: []string{"sections[0].questions[0].logic"},
+		},
+		{
+			name: "question in section",
+			definition: Definition{Title: "Colors", Sections: []DefinitionSection{
+				{Questions: []DefinitionQuestion{valid()}},
+				{Questions: []DefinitionQuestion{valid(), {Type: QuestionTypeShortAnswer}}},
+			}},
+			want: []string{"sections[1].questions[1].text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.definition.validate()
+
+			var definitionErr *DefinitionError
+			if !errors.As(err, &definitionErr) {
+				t.Fatalf("validate() error = %v, want a *DefinitionError", err)
+			}
+
+			var fields []string
+			for _, fieldErr := range definitionErr.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			if !slices.Equal(fields, tt.want) {
+				t.Errorf("validate() reported %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package form
+
+import (
+	"database-final-project/internal"
+
+	"github.com/google/uuid"
+)
+
+// maxDescriptionLength bounds the Markdown description of a form.
+const maxDescriptionLength = 10000
+
+// renderDescriptions fills in the sanitized HTML of the form, section and
+// question descriptions.
+func (f *QuestionsForm) renderDescriptions() error {
+	var err error
+	f.DescriptionHTML, err = internal.RenderMarkdown(f.Description)
+	if err != nil {
+		return err
+	}
+
+	rendered := make(map[uuid.UUID]string, len(f.Questions))
+	for i, q := range f.Questions {
+		f.Questions[i].DescriptionHTML, err = internal.RenderMarkdown(q.Description)
+		if err != nil {
+			return err
+		}
+		rendered[q.QuestionID] = f.Questions[i].DescriptionHTML
+	}
+
+	for i, s := range f.Sections {
+		f.Sections[i].DescriptionHTML, err = internal.RenderMarkdown(s.Description)
+		if err != nil {
+			return err
+		}
+		for j, q := range s.Questions {
+			f.Sections[i].Questions[j].DescriptionHTML = rendered[q.QuestionID]
+		}
+	}
+
+	return nil
+}
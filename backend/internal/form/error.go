@@ -1,7 +1,50 @@
 package form
 
-import "errors"
+import (
+	"database-final-project/internal"
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrFormNotFound = errors.New("form not found")
+	ErrFormNotFound         = errors.New("form not found")
+	ErrPermissionDenied     = errors.New("your role on the form does not allow this")
+	ErrInvalidQuestionOrder = errors.New("order must list every question of the form exactly once")
+	ErrInvalidSectionOrder  = errors.New("order must list every section of the form exactly once, and only forms with sections are ordered by section")
+	ErrInvalidSort          = errors.New("sort must be one of created_at, updated_at or title")
+	ErrQuestionsAndSections = errors.New("give either questions or sections, not both")
+	ErrSectionTitleTooLong  = errors.New("section title must be at most 255 characters")
+	ErrDescriptionTooLong   = errors.New("description must be at most 10000 characters")
+	ErrInvalidStatus        = errors.New("status must be one of draft, open or closed")
+	ErrInvalidSchedule      = errors.New("closes_at must be after opens_at")
+	ErrInvalidMaxResponses  = errors.New("max_responses must be at least 1")
+
+	ErrInvalidIdentity          = errors.New("respondent_identity must be one of anonymous, optional or required")
+	ErrOneResponseNeedsIdentity = errors.New("one_response_per_respondent needs respondent_identity required")
+	ErrIdentityRequired         = errors.New("this form needs you to sign in or give your email")
+	ErrSignInRequired           = errors.New("this form takes one response per person, so it needs you to sign in")
+	ErrInvalidEmail             = errors.New("respondent_email must be a valid email address")
+	ErrInvitationRequired       = errors.New("this form needs a valid invitation")
+
+	// ErrNotAcceptingResponses is wrapped by every reason a form turns a
+	// submission away.
+	ErrNotAcceptingResponses = errors.New("form is not accepting responses")
+	ErrFormDraft             = fmt.Errorf("%w: the form is still a draft", ErrNotAcceptingResponses)
+	ErrFormClosed            = fmt.Errorf("%w: the form is closed", ErrNotAcceptingResponses)
+	ErrFormNotOpenYet        = fmt.Errorf("%w: the form has not opened yet", ErrNotAcceptingResponses)
+	ErrFormDeadlinePassed    = fmt.Errorf("%w: the form has passed its closing time", ErrNotAcceptingResponses)
+	ErrResponseLimitReached  = fmt.Errorf("%w: the form has reached its maximum number of responses", ErrNotAcceptingResponses)
 )
+
+// DefinitionError lists every problem found in an imported form definition.
+type DefinitionError struct {
+	Errors []internal.FieldError
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("definition has %d problem(s)", len(e.Errors))
+}
+
+func (e *DefinitionError) add(field string, message string) {
+	e.Errors = append(e.Errors, internal.FieldError{Field: field, Message: message})
+}
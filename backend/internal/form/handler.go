@@ -1,34 +1,128 @@
 package form
 
 import (
+	"bytes"
 	"context"
 	"database-final-project/internal"
 	"database-final-project/internal/answer"
+	"database-final-project/internal/auth"
+	"database-final-project/internal/collaborator"
+	"database-final-project/internal/export"
+	"database-final-project/internal/invitation"
+	"database-final-project/internal/options"
+	"database-final-project/internal/question"
+	"database-final-project/internal/section"
 	"database-final-project/internal/submission"
+	"database-final-project/internal/summary"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
+// CreateRequest creates a form with either a flat list of questions or
+// questions grouped into sections. The form opens right away and takes
+// anonymous responses unless the lifecycle and respondent fields say
+// otherwise.
 type CreateRequest struct {
-	Title     string            `json:"title" validate:"required,min=1,max=255"`
-	Questions []QuestionRequest `json:"questions,omitempty"`
+	Title       string            `json:"title" validate:"required,min=1,max=255"`
+	Description string            `json:"description,omitempty" validate:"max=10000"`
+	Questions   []QuestionRequest `json:"questions,omitempty"`
+	Sections    []SectionRequest  `json:"sections,omitempty"`
+	Lifecycle
+	Respondents
 }
 
+// SectionRequest is a page of a new form with the questions shown on it.
+type SectionRequest struct {
+	Title       string            `json:"title" validate:"max=255"`
+	Description string            `json:"description"`
+	Questions   []QuestionRequest `json:"questions"`
+}
+
+// UpdateRequest replaces the title and, when present, the description and
+// the whole question list of a form. Leaving Questions and Sections out keeps
+// the questions as they are; sending Questions removes the form's sections.
 type UpdateRequest struct {
-	Title string `json:"title" validate:"required,min=1,max=255"`
+	Title       string                  `json:"title" validate:"required,min=1,max=255"`
+	Description *string                 `json:"description,omitempty" validate:"omitempty,max=10000"`
+	Questions   []UpdateQuestionRequest `json:"questions,omitempty"`
+	Sections    []UpdateSectionRequest  `json:"sections,omitempty"`
+}
+
+// UpdateSectionRequest updates the section with SectionID, or creates a new
+// one when SectionID is omitted.
+type UpdateSectionRequest struct {
+	SectionID   uuid.UUID               `json:"section_id,omitempty"`
+	Title       string                  `json:"title" validate:"max=255"`
+	Description string                  `json:"description"`
+	Questions   []UpdateQuestionRequest `json:"questions"`
 }
 
 type QuestionRequest struct {
-	QuestionType QuestionType `json:"type" validate:"required,oneof=short_answer select multiselect"`
-	IsRequired   bool         `json:"is_required"`
-	QuestionText string       `json:"question_text" validate:"required,min=1,max=1000"`
-	Options      []string     `json:"options,omitempty"`
+	QuestionType QuestionType         `json:"type" validate:"required,oneof=short_answer paragraph select multiselect number email url date time rating linear_scale"`
+	IsRequired   bool                 `json:"is_required"`
+	QuestionText string               `json:"question_text" validate:"required,min=1,max=1000"`
+	Description  string               `json:"description,omitempty" validate:"max=5000"`
+	Config       *question.Config     `json:"config,omitempty"`
+	Validation   *question.Validation `json:"validation,omitempty"`
+	Logic        *question.Logic      `json:"logic,omitempty"`
+	Options      []string             `json:"options,omitempty"`
+}
+
+// UpdateQuestionRequest updates the question with QuestionID, or creates a new
+// one when QuestionID is omitted. Options follow the same rule with OptionID.
+type UpdateQuestionRequest struct {
+	QuestionID   uuid.UUID            `json:"question_id,omitempty"`
+	QuestionType QuestionType         `json:"type" validate:"required,oneof=short_answer paragraph select multiselect number email url date time rating linear_scale"`
+	IsRequired   bool                 `json:"is_required"`
+	QuestionText string               `json:"question_text" validate:"required,min=1,max=1000"`
+	Description  string               `json:"description,omitempty" validate:"max=5000"`
+	Config       *question.Config     `json:"config,omitempty"`
+	Validation   *question.Validation `json:"validation,omitempty"`
+	Logic        *question.Logic      `json:"logic,omitempty"`
+	Options      []options.Request    `json:"options,omitempty"`
+}
+
+// DuplicateRequest optionally names the copy of a form.
+type DuplicateRequest struct {
+	Title string `json:"title,omitempty" validate:"max=255"`
 }
 
+// OrderQuestionRequest places a question at its index in the order request.
+// OptionIDs, when present, lists the question's options in their new order.
+type OrderQuestionRequest struct {
+	QuestionID uuid.UUID   `json:"question_id" validate:"required"`
+	OptionIDs  []uuid.UUID `json:"option_ids,omitempty"`
+}
+
+// OrderSectionRequest places a section at its index in the order request,
+// followed by its questions in order.
+type OrderSectionRequest struct {
+	SectionID uuid.UUID              `json:"section_id" validate:"required"`
+	Questions []OrderQuestionRequest `json:"questions"`
+}
+
+// OrderRequest lists the questions of a form in their new order, or for a
+// form with sections, its sections.
+type OrderRequest struct {
+	Questions []OrderQuestionRequest `json:"questions,omitempty"`
+	Sections  []OrderSectionRequest  `json:"sections,omitempty"`
+}
+
+// AnswersRequest is a submission. RespondentEmail is only recorded by forms
+// that collect respondent identity.
 type AnswersRequest struct {
-	Answers []AnswerRequest `json:"answers" validate:"required,min=1"`
+	Answers         []AnswerRequest `json:"answers" validate:"required,min=1"`
+	RespondentEmail string          `json:"respondent_email,omitempty"`
 }
 
 type AnswerRequest struct {
@@ -38,47 +132,258 @@ type AnswerRequest struct {
 }
 
 type Store interface {
-	GetAll(ctx context.Context) ([]QuestionsForm, error)
+	List(ctx context.Context, params ListParams) (ListResponse, error)
 	GetByID(ctx context.Context, id uuid.UUID) (QuestionsForm, error)
-	Create(ctx context.Context, title string, questionRequest []QuestionRequest) (QuestionsForm, error)
+	Create(ctx context.Context, ownerID uuid.UUID, title string, description string, lifecycle Lifecycle, respondents Respondents, questionRequest []QuestionRequest, sectionRequest []SectionRequest) (QuestionsForm, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	Update(ctx context.Context, id uuid.UUID, title string) (QuestionsForm, error)
+	Update(ctx context.Context, id uuid.UUID, title string, description *string, questionRequest []UpdateQuestionRequest, sectionRequest []UpdateSectionRequest) (QuestionsForm, error)
+	Reorder(ctx context.Context, id uuid.UUID, orderRequest []OrderQuestionRequest, sectionOrder []OrderSectionRequest) (QuestionsForm, error)
+	Duplicate(ctx context.Context, id uuid.UUID, ownerID uuid.UUID, title string) (QuestionsForm, error)
+	CheckPermission(ctx context.Context, id uuid.UUID, userID uuid.UUID, required collaborator.Role) error
+	CheckOpen(ctx context.Context, id uuid.UUID, lifecycle Lifecycle) error
+	SetLifecycle(ctx context.Context, id uuid.UUID, lifecycle Lifecycle) (QuestionsForm, error)
+	SetRespondents(ctx context.Context, id uuid.UUID, respondents Respondents) (QuestionsForm, error)
 }
 
 type submissionStore interface {
-	GetByID(ctx context.Context, id uuid.UUID) ([]submission.AnswersSubmission, error)
-	Create(ctx context.Context, formID uuid.UUID, answers []answer.Request) error
+	List(ctx context.Context, formID uuid.UUID, params submission.ListParams) (submission.ListResponse, error)
+	Get(ctx context.Context, formID uuid.UUID, id uuid.UUID) (submission.AnswersSubmission, error)
+	Create(ctx context.Context, formID uuid.UUID, respondent submission.Respondent, idempotency submission.Idempotency, answers []answer.Request) (bool, error)
+	Update(ctx context.Context, formID uuid.UUID, id uuid.UUID, answers []answer.Request) (submission.AnswersSubmission, error)
+	Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error
+}
+
+type summaryStore interface {
+	Get(ctx context.Context, formID uuid.UUID) (summary.Response, error)
+}
+
+type exportStore interface {
+	Export(ctx context.Context, w io.Writer, formID uuid.UUID, format export.Format, params submission.ListParams) error
+}
+
+type collaboratorStore interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]collaborator.Response, error)
+	Grant(ctx context.Context, formID uuid.UUID, username string, role collaborator.Role) (collaborator.Response, error)
+	Revoke(ctx context.Context, formID uuid.UUID, userID uuid.UUID) error
+}
+
+type invitationStore interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]invitation.Response, error)
+	Create(ctx context.Context, formID uuid.UUID, count int32, maxUses int32, expiresAt *time.Time) ([]invitation.CreatedResponse, error)
+	Check(ctx context.Context, formID uuid.UUID, token string) error
+	Revoke(ctx context.Context, formID uuid.UUID, id uuid.UUID) error
 }
 
 type Handler struct {
-	logger          *zap.Logger
-	store           Store
-	submissionStore submissionStore
+	logger            *zap.Logger
+	store             Store
+	submissionStore   submissionStore
+	summaryStore      summaryStore
+	exportStore       exportStore
+	collaboratorStore collaboratorStore
+	invitationStore   invitationStore
 }
 
-func NewHandler(logger *zap.Logger, store Store, submissionStore submissionStore) *Handler {
+func NewHandler(logger *zap.Logger, store Store, submissionStore submissionStore, summaryStore summaryStore, exportStore exportStore, collaboratorStore collaboratorStore, invitationStore invitationStore) *Handler {
 	return &Handler{
-		logger:          logger,
-		store:           store,
-		submissionStore: submissionStore,
+		logger:            logger,
+		store:             store,
+		submissionStore:   submissionStore,
+		summaryStore:      summaryStore,
+		exportStore:       exportStore,
+		collaboratorStore: collaboratorStore,
+		invitationStore:   invitationStore,
+	}
+}
+
+// Router is where the form routes are registered, an *http.ServeMux in
+// cmd/backend.
+type Router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// RegisterRoutes registers every form route on router.
+func (h *Handler) RegisterRoutes(router Router) {
+	router.HandleFunc("GET /api/forms", h.GetAll)
+	router.HandleFunc("GET /api/forms/{id}", h.GetByID)
+	router.HandleFunc("POST /api/forms", h.Create)
+	router.HandleFunc("POST /api/forms/import", h.Import)
+	router.HandleFunc("GET /api/forms/{id}/definition", h.GetDefinition)
+	router.HandleFunc("PUT /api/forms/{id}", h.Update)
+	router.HandleFunc("DELETE /api/forms/{id}", h.Delete)
+	router.HandleFunc("PUT /api/forms/{id}/order", h.Reorder)
+	router.HandleFunc("POST /api/forms/{id}/duplicate", h.Duplicate)
+	router.HandleFunc("PUT /api/forms/{id}/lifecycle", h.SetLifecycle)
+	router.HandleFunc("PUT /api/forms/{id}/respondents", h.SetRespondents)
+	router.HandleFunc("GET /api/forms/{id}/collaborators", h.GetCollaborators)
+	router.HandleFunc("POST /api/forms/{id}/collaborators", h.GrantCollaborator)
+	router.HandleFunc("DELETE /api/forms/{id}/collaborators/{user_id}", h.RevokeCollaborator)
+	router.HandleFunc("GET /api/forms/{id}/invitations", h.GetInvitations)
+	router.HandleFunc("POST /api/forms/{id}/invitations", h.CreateInvitations)
+	router.HandleFunc("DELETE /api/forms/{id}/invitations/{invitation_id}", h.RevokeInvitation)
+	router.HandleFunc("GET /api/forms/{id}/summary", h.GetSummary)
+	router.HandleFunc("GET /api/forms/{id}/answers", h.GetAllAnswer)
+	router.HandleFunc("POST /api/forms/{id}/answers", h.CreateAnswer)
+	router.HandleFunc("GET /api/forms/{id}/answers/export", h.ExportAnswers)
+	router.HandleFunc("GET /api/forms/{id}/answers/{submission_id}", h.GetAnswer)
+	router.HandleFunc("PUT /api/forms/{id}/answers/{submission_id}", h.UpdateAnswer)
+	router.HandleFunc("DELETE /api/forms/{id}/answers/{submission_id}", h.DeleteAnswer)
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// currentUser returns the signed-in user, writing a 401 response when there
+// is none and a 403 response when the API key it signed in with lacks the
+// scope.
+func (h *Handler) currentUser(w http.ResponseWriter, r *http.Request, scope auth.Scope) (auth.User, bool) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Sign in required"))
+		return auth.User{}, false
+	}
+	if !user.HasScope(scope) {
+		internal.WriteResponseToBody(w, h.logger, http.StatusForbidden, internal.NewForbiddenError("API key lacks the "+string(scope)+" scope"))
+		return auth.User{}, false
 	}
+	return user, true
 }
 
+// authorize reports whether the signed-in user has at least the required role
+// on the form and the scope, writing the error response when not. Filling in a form needs
+// no authorization.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, id uuid.UUID, required collaborator.Role, scope auth.Scope) bool {
+	user, ok := h.currentUser(w, r, scope)
+	if !ok {
+		return false
+	}
+
+	err := h.store.CheckPermission(r.Context(), id, user.ID, required)
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return false
+		}
+		if errors.Is(err, ErrPermissionDenied) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusForbidden, internal.NewForbiddenError(err.Error()))
+			return false
+		}
+		h.logger.Error("Failed to check form permission", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to check form permission"))
+		return false
+	}
+
+	return true
+}
+
+// readable reports whether the signed-in user may read the form as a
+// collaborator, whatever state it is in.
+func (h *Handler) readable(r *http.Request, id uuid.UUID) (bool, error) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || !user.HasScope(auth.ScopeFormsRead) {
+		return false, nil
+	}
+
+	err := h.store.CheckPermission(r.Context(), id, user.ID, collaborator.RoleViewer)
+	if err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// invited reports whether the request carries a usable invitation to an
+// invite-only form as the invitation query parameter.
+func (h *Handler) invited(r *http.Request, id uuid.UUID) (bool, error) {
+	token := r.URL.Query().Get("invitation")
+	if token == "" {
+		return false, nil
+	}
+
+	err := h.invitationStore.Check(r.Context(), id, token)
+	if err != nil {
+		if errors.Is(err, invitation.ErrInvalidInvitation) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetAll lists the forms the signed-in user can manage.
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	forms, err := h.store.GetAll(r.Context())
+	user, ok := h.currentUser(w, r, auth.ScopeFormsRead)
+	if !ok {
+		return
+	}
+
+	params, err := parseListParams(r)
 	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+	params.UserID = user.ID
+
+	forms, err := h.store.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, internal.ErrInvalidCursor) || errors.Is(err, ErrInvalidSort) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
 		h.logger.Error("Failed to get forms", zap.Error(err))
 		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get forms"))
 		return
 	}
 
-	if forms == nil {
-		forms = []QuestionsForm{}
+	if params.Summary {
+		internal.WriteResponseToBody(w, h.logger, http.StatusOK, forms.summary())
+		return
 	}
-
 	internal.WriteResponseToBody(w, h.logger, http.StatusOK, forms)
 }
 
+// parseListParams reads limit, cursor, sort, q and summary from the query
+// string. A "-" in front of the sort field sorts in descending order.
+func parseListParams(r *http.Request) (ListParams, error) {
+	query := r.URL.Query()
+
+	params := ListParams{
+		Limit:  defaultPageSize,
+		Cursor: query.Get("cursor"),
+		Sort:   SortCreatedAt,
+		Search: query.Get("q"),
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return ListParams{}, errors.New("limit must be between 1 and " + strconv.Itoa(maxPageSize))
+		}
+		params.Limit = int32(limit)
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		params.Descending = strings.HasPrefix(sort, "-")
+		params.Sort = strings.TrimPrefix(sort, "-")
+	}
+
+	if summaryStr := query.Get("summary"); summaryStr != "" {
+		summary, err := strconv.ParseBool(summaryStr)
+		if err != nil {
+			return ListParams{}, errors.New("summary must be a boolean")
+		}
+		params.Summary = summary
+	}
+
+	return params, nil
+}
+
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 
@@ -91,15 +396,80 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	form, err := h.store.GetByID(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
 		h.logger.Error("Failed to get form by ID", zap.Error(err))
 		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
 		return
 	}
 
+	// Collaborators see the form in any state. Everyone else only sees a form
+	// while it takes responses, and not who owns it; any other form is not
+	// found for them.
+	readable, err := h.readable(r, id)
+	if err != nil {
+		h.logger.Error("Failed to check form permission", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
+		return
+	}
+	if !readable {
+		err = h.store.CheckOpen(r.Context(), id, form.Lifecycle)
+		if err != nil {
+			if errors.Is(err, ErrNotAcceptingResponses) {
+				internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+				return
+			}
+			h.logger.Error("Failed to check form lifecycle", zap.Error(err))
+			internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
+			return
+		}
+
+		if form.InviteOnly {
+			invited, err := h.invited(r, id)
+			if err != nil {
+				h.logger.Error("Failed to check invitation", zap.Error(err))
+				internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
+				return
+			}
+			if !invited {
+				internal.WriteResponseToBody(w, h.logger, http.StatusForbidden, internal.NewForbiddenError(ErrInvitationRequired.Error()))
+				return
+			}
+		}
+
+		form.OwnerID = nil
+		form.MaxResponses = nil
+	}
+
+	// With html=true the Markdown descriptions are also returned as sanitized
+	// HTML, so clients never have to render user input themselves.
+	if htmlStr := r.URL.Query().Get("html"); htmlStr != "" {
+		renderHTML, err := strconv.ParseBool(htmlStr)
+		if err != nil {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("html must be a boolean"))
+			return
+		}
+		if renderHTML {
+			err = form.renderDescriptions()
+			if err != nil {
+				h.logger.Error("Failed to render form descriptions", zap.Error(err))
+				internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
+				return
+			}
+		}
+	}
+
 	internal.WriteResponseToBody(w, h.logger, http.StatusOK, form)
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r, auth.ScopeFormsWrite)
+	if !ok {
+		return
+	}
+
 	var req CreateRequest
 	err := internal.ParseRequestFromBody(r, h.logger, &req)
 	if err != nil {
@@ -107,8 +477,16 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	form, err := h.store.Create(r.Context(), req.Title, req.Questions)
+	form, err := h.store.Create(r.Context(), user.ID, req.Title, req.Description, req.Lifecycle, req.Respondents, req.Questions, req.Sections)
 	if err != nil {
+		if errors.Is(err, ErrQuestionsAndSections) || errors.Is(err, ErrSectionTitleTooLong) ||
+			errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidSchedule) || errors.Is(err, ErrInvalidMaxResponses) ||
+			errors.Is(err, ErrInvalidIdentity) || errors.Is(err, ErrOneResponseNeedsIdentity) ||
+			errors.Is(err, ErrDescriptionTooLong) || errors.Is(err, question.ErrDescriptionTooLong) ||
+			errors.Is(err, question.ErrInvalidQuestionType) || errors.Is(err, question.ErrInvalidConfig) || errors.Is(err, question.ErrInvalidValidation) || errors.Is(err, question.ErrInvalidLogic) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
 		h.logger.Error("Failed to create form", zap.Error(err))
 		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to create form"))
 		return
@@ -117,6 +495,161 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, form)
 }
 
+// GetDefinition returns the form as a portable Definition, as JSON or, with
+// format=yaml, as YAML.
+func (h *Handler) GetDefinition(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeFormsRead) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "yaml" {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("format must be json or yaml"))
+		return
+	}
+
+	form, err := h.store.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		h.logger.Error("Failed to get form by ID", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form"))
+		return
+	}
+
+	definition := toDefinition(form)
+	if format != "yaml" {
+		internal.WriteResponseToBody(w, h.logger, http.StatusOK, definition)
+		return
+	}
+
+	body, err := yaml.Marshal(definition)
+	if err != nil {
+		h.logger.Error("Failed to marshal form definition", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get form definition"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body)
+	if err != nil {
+		h.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// Import creates a form from a Definition sent as JSON or, with a YAML
+// Content-Type, as YAML.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r, auth.ScopeFormsWrite)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	definition, err := parseDefinition(body, isYAML(r.Header.Get("Content-Type")))
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	err = definition.validate()
+	if err != nil {
+		var definitionErr *DefinitionError
+		if errors.As(err, &definitionErr) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnprocessableEntity, internal.NewUnprocessableEntityError("Invalid form definition", definitionErr.Errors))
+			return
+		}
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	questionRequests, sectionRequests := definition.requests()
+	form, err := h.store.Create(r.Context(), user.ID, strings.TrimSpace(definition.Title), definition.Description, Lifecycle{}, Respondents{}, questionRequests, sectionRequests)
+	if err != nil {
+		if errors.Is(err, question.ErrInvalidQuestionType) || errors.Is(err, question.ErrInvalidConfig) || errors.Is(err, question.ErrInvalidValidation) || errors.Is(err, question.ErrInvalidLogic) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to import form", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to import form"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, form)
+}
+
+// Duplicate copies a form. The request body is optional; without a title the
+// copy is named after the original. The copy carries the form's questions and
+// settings, so only editors may make one; viewers can still read the
+// definition.
+func (h *Handler) Duplicate(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	var req DuplicateRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if utf8.RuneCountInString(title) > 255 {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("title must be at most 255 characters"))
+		return
+	}
+
+	// authorize has made sure there is a signed-in user.
+	user, _ := auth.UserFromContext(r.Context())
+	form, err := h.store.Duplicate(r.Context(), id, user.ID, title)
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		h.logger.Error("Failed to duplicate form", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to duplicate form"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, form)
+}
+
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 
@@ -127,6 +660,10 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorize(w, r, id, collaborator.RoleOwner, auth.ScopeFormsWrite) {
+		return
+	}
+
 	err = h.store.Delete(r.Context(), id)
 	if err != nil {
 		h.logger.Error("Failed to delete form", zap.Error(err))
@@ -147,6 +684,10 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
 	var req UpdateRequest
 	err = internal.ParseRequestFromBody(r, h.logger, &req)
 	if err != nil {
@@ -154,8 +695,19 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedForm, err := h.store.Update(r.Context(), id, req.Title)
+	updatedForm, err := h.store.Update(r.Context(), id, req.Title, req.Description, req.Questions, req.Sections)
 	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		if errors.Is(err, question.ErrQuestionNotFound) || errors.Is(err, options.ErrOptionNotFound) || errors.Is(err, section.ErrSectionNotFound) ||
+			errors.Is(err, ErrQuestionsAndSections) || errors.Is(err, ErrSectionTitleTooLong) ||
+			errors.Is(err, ErrDescriptionTooLong) || errors.Is(err, question.ErrDescriptionTooLong) ||
+			errors.Is(err, question.ErrInvalidQuestionType) || errors.Is(err, question.ErrInvalidConfig) || errors.Is(err, question.ErrInvalidValidation) || errors.Is(err, question.ErrInvalidLogic) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
 		h.logger.Error("Failed to update form", zap.Error(err))
 		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to update form"))
 		return
@@ -164,7 +716,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	internal.WriteResponseToBody(w, h.logger, http.StatusOK, updatedForm)
 }
 
-func (h *Handler) GetAllAnswer(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) Reorder(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 
 	id, err := uuid.Parse(idStr)
@@ -174,17 +726,38 @@ func (h *Handler) GetAllAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	answers, err := h.submissionStore.GetByID(r.Context(), id)
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	var req OrderRequest
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
 	if err != nil {
-		h.logger.Error("Failed to get answers by form ID", zap.Error(err))
-		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get answers"))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
 		return
 	}
 
-	internal.WriteResponseToBody(w, h.logger, http.StatusOK, answers)
+	reorderedForm, err := h.store.Reorder(r.Context(), id, req.Questions, req.Sections)
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		if errors.Is(err, ErrInvalidQuestionOrder) || errors.Is(err, ErrInvalidSectionOrder) || errors.Is(err, question.ErrInvalidOptionOrder) || errors.Is(err, question.ErrInvalidLogic) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to reorder form", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to reorder form"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, reorderedForm)
 }
 
-func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
+// SetLifecycle replaces the status, schedule and response limit of a form.
+// Fields left out are cleared and a missing status opens the form.
+func (h *Handler) SetLifecycle(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 
 	id, err := uuid.Parse(idStr)
@@ -194,31 +767,668 @@ func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req AnswersRequest
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	var req Lifecycle
 	err = internal.ParseRequestFromBody(r, h.logger, &req)
 	if err != nil {
 		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
 		return
 	}
 
-	err = h.submissionStore.Create(r.Context(), id, convertToAnswerRequests(req.Answers))
+	form, err := h.store.SetLifecycle(r.Context(), id, req)
 	if err != nil {
-		h.logger.Error("Failed to create answers", zap.Error(err))
-		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to create answers"))
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		if errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidSchedule) || errors.Is(err, ErrInvalidMaxResponses) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to update form lifecycle", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to update form lifecycle"))
 		return
 	}
 
-	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, form)
 }
 
-func convertToAnswerRequests(answerRequests []AnswerRequest) []answer.Request {
-	var answers []answer.Request
-	for _, ar := range answerRequests {
-		answers = append(answers, answer.Request{
-			QuestionID:    ar.QuestionID,
-			AnswerText:    ar.AnswerText,
-			AnswerOptions: ar.AnswersOptions,
-		})
+// SetRespondents replaces what a form records about who fills it in.
+func (h *Handler) SetRespondents(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
 	}
-	return answers
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	var req Respondents
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	form, err := h.store.SetRespondents(r.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		if errors.Is(err, ErrInvalidIdentity) || errors.Is(err, ErrOneResponseNeedsIdentity) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to update form respondent settings", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to update form respondent settings"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, form)
+}
+
+func (h *Handler) GetAllAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeResponsesRead) {
+		return
+	}
+
+	params, err := parseSubmissionListParams(r)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	answers, err := h.submissionStore.List(r.Context(), id, params)
+	if err != nil {
+		if errors.Is(err, internal.ErrInvalidCursor) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to get answers by form ID", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get answers"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, answers)
+}
+
+// parseSubmissionListParams reads limit, cursor, sort (created_at or
+// -created_at), the from/to submission time range and the question, option and
+// contains answer filters from the query string.
+func parseSubmissionListParams(r *http.Request) (submission.ListParams, error) {
+	query := r.URL.Query()
+
+	params := submission.ListParams{
+		Limit:        defaultPageSize,
+		Cursor:       query.Get("cursor"),
+		TextContains: query.Get("contains"),
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return submission.ListParams{}, errors.New("limit must be between 1 and " + strconv.Itoa(maxPageSize))
+		}
+		params.Limit = int32(limit)
+	}
+
+	switch query.Get("sort") {
+	case "", "created_at":
+	case "-created_at":
+		params.Descending = true
+	default:
+		return submission.ListParams{}, errors.New("sort must be created_at or -created_at")
+	}
+
+	var err error
+	if fromStr := query.Get("from"); fromStr != "" {
+		params.CreatedFrom, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return submission.ListParams{}, errors.New("from must be an RFC 3339 timestamp")
+		}
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		params.CreatedTo, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return submission.ListParams{}, errors.New("to must be an RFC 3339 timestamp")
+		}
+	}
+
+	if questionStr := query.Get("question"); questionStr != "" {
+		params.QuestionID, err = uuid.Parse(questionStr)
+		if err != nil {
+			return submission.ListParams{}, errors.New("question must be a question ID")
+		}
+	}
+	if optionStr := query.Get("option"); optionStr != "" {
+		params.OptionID, err = uuid.Parse(optionStr)
+		if err != nil {
+			return submission.ListParams{}, errors.New("option must be an option ID")
+		}
+	}
+	if params.QuestionID == uuid.Nil && (params.OptionID != uuid.Nil || params.TextContains != "") {
+		return submission.ListParams{}, errors.New("option and contains filters require a question")
+	}
+
+	return params, nil
+}
+
+// ExportAnswers streams the form's submissions as csv, xlsx or jsonl. It takes
+// the same filters as GetAllAnswer but always exports every matching submission.
+func (h *Handler) ExportAnswers(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeResponsesRead) {
+		return
+	}
+
+	format := export.Format(r.URL.Query().Get("format"))
+	switch format {
+	case "":
+		format = export.FormatCSV
+	case export.FormatCSV, export.FormatXLSX, export.FormatJSONL:
+	default:
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(export.ErrUnsupportedFormat.Error()))
+		return
+	}
+
+	params, err := parseSubmissionListParams(r)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+id.String()+`.`+string(format)+`"`)
+
+	// The response is streamed, so once the export has started a failure can
+	// only be logged; the client sees a truncated file.
+	err = h.exportStore.Export(r.Context(), w, id, format, params)
+	if err != nil {
+		h.logger.Error("Failed to export answers", zap.String("form_id", id.String()), zap.Error(err))
+	}
+}
+
+func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	// The body is kept as sent: a retry must repeat it byte for byte to be
+	// recognised by its Idempotency-Key.
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error("Failed to read answers request", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req AnswersRequest
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	// Whoever is signed in is the respondent; the form decides whether that
+	// is recorded.
+	respondent := submission.Respondent{
+		Email:      req.RespondentEmail,
+		Invitation: r.URL.Query().Get("invitation"),
+	}
+	if user, ok := auth.UserFromContext(r.Context()); ok {
+		respondent.UserID = &user.ID
+	}
+
+	// A retry carries the key of the first attempt.
+	idempotency := submission.Idempotency{
+		Key:     r.Header.Get("Idempotency-Key"),
+		Request: body,
+	}
+
+	replayed, err := h.submissionStore.Create(r.Context(), id, respondent, idempotency, convertToAnswerRequests(req.Answers))
+	if err != nil {
+		if errors.Is(err, ErrFormNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Form not found"))
+			return
+		}
+		if errors.Is(err, submission.ErrInvalidIdempotencyKey) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		if errors.Is(err, submission.ErrIdempotencyKeyReused) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnprocessableEntity, internal.NewUnprocessableEntityError(err.Error(), nil))
+			return
+		}
+		if errors.Is(err, ErrNotAcceptingResponses) || errors.Is(err, ErrInvitationRequired) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusForbidden, internal.NewForbiddenError(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrSignInRequired) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrIdentityRequired) || errors.Is(err, ErrInvalidEmail) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		if errors.Is(err, submission.ErrAlreadyResponded) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusConflict, internal.NewConflictError(err.Error()))
+			return
+		}
+		var validationErr *submission.ValidationError
+		if errors.As(err, &validationErr) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnprocessableEntity, internal.NewUnprocessableEntityError("Invalid answers", convertToFieldErrors(validationErr)))
+			return
+		}
+		h.logger.Error("Failed to create answers", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to create answers"))
+		return
+	}
+
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+}
+
+func (h *Handler) GetAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeResponsesRead) {
+		return
+	}
+
+	submissionIDStr := r.PathValue("submission_id")
+
+	submissionID, err := uuid.Parse(submissionIDStr)
+	if err != nil {
+		h.logger.Error("Invalid submission ID", zap.String("submission_id", submissionIDStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid submission ID"))
+		return
+	}
+
+	answers, err := h.submissionStore.Get(r.Context(), id, submissionID)
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmissionNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Submission not found"))
+			return
+		}
+		h.logger.Error("Failed to get submission", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get answers"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, answers)
+}
+
+func (h *Handler) UpdateAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeResponsesWrite) {
+		return
+	}
+
+	submissionIDStr := r.PathValue("submission_id")
+
+	submissionID, err := uuid.Parse(submissionIDStr)
+	if err != nil {
+		h.logger.Error("Invalid submission ID", zap.String("submission_id", submissionIDStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid submission ID"))
+		return
+	}
+
+	var req AnswersRequest
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	answers, err := h.submissionStore.Update(r.Context(), id, submissionID, convertToAnswerRequests(req.Answers))
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmissionNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Submission not found"))
+			return
+		}
+		var validationErr *submission.ValidationError
+		if errors.As(err, &validationErr) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnprocessableEntity, internal.NewUnprocessableEntityError("Invalid answers", convertToFieldErrors(validationErr)))
+			return
+		}
+		h.logger.Error("Failed to update answers", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to update answers"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, answers)
+}
+
+func (h *Handler) DeleteAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeResponsesWrite) {
+		return
+	}
+
+	submissionIDStr := r.PathValue("submission_id")
+
+	submissionID, err := uuid.Parse(submissionIDStr)
+	if err != nil {
+		h.logger.Error("Invalid submission ID", zap.String("submission_id", submissionIDStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid submission ID"))
+		return
+	}
+
+	err = h.submissionStore.Delete(r.Context(), id, submissionID)
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmissionNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Submission not found"))
+			return
+		}
+		h.logger.Error("Failed to delete submission", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to delete answers"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+}
+
+func convertToAnswerRequests(answerRequests []AnswerRequest) []answer.Request {
+	var answers []answer.Request
+	for _, ar := range answerRequests {
+		answers = append(answers, answer.Request{
+			QuestionID:    ar.QuestionID,
+			AnswerText:    ar.AnswerText,
+			AnswerOptions: ar.AnswersOptions,
+		})
+	}
+	return answers
+}
+
+func convertToFieldErrors(validationErr *submission.ValidationError) []internal.FieldError {
+	fieldErrors := make([]internal.FieldError, len(validationErr.Errors))
+	for i, e := range validationErr.Errors {
+		fieldErrors[i] = internal.FieldError{
+			Field:   e.QuestionID.String(),
+			Message: e.Message,
+		}
+	}
+	return fieldErrors
+}
+
+func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeResponsesRead) {
+		return
+	}
+
+	formSummary, err := h.summaryStore.Get(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get form summary", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get summary"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, formSummary)
+}
+
+func (h *Handler) GetCollaborators(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeFormsRead) {
+		return
+	}
+
+	collaborators, err := h.collaboratorStore.GetByFormID(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get collaborators", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get collaborators"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, collaborators)
+}
+
+// GrantCollaborator gives a user a role on the form, or changes the role they
+// already have.
+func (h *Handler) GrantCollaborator(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleOwner, auth.ScopeFormsWrite) {
+		return
+	}
+
+	var req collaborator.Request
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	granted, err := h.collaboratorStore.Grant(r.Context(), id, req.Username, req.Role)
+	if err != nil {
+		if errors.Is(err, collaborator.ErrUserNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("User not found"))
+			return
+		}
+		if errors.Is(err, collaborator.ErrInvalidRole) || errors.Is(err, collaborator.ErrFormOwner) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to grant collaborator", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to grant collaborator"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, granted)
+}
+
+func (h *Handler) RevokeCollaborator(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleOwner, auth.ScopeFormsWrite) {
+		return
+	}
+
+	userIDStr := r.PathValue("user_id")
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		h.logger.Error("Invalid user ID", zap.String("user_id", userIDStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid user ID"))
+		return
+	}
+
+	err = h.collaboratorStore.Revoke(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, collaborator.ErrCollaboratorNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Collaborator not found"))
+			return
+		}
+		h.logger.Error("Failed to revoke collaborator", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to revoke collaborator"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+}
+
+func (h *Handler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleViewer, auth.ScopeFormsRead) {
+		return
+	}
+
+	invitations, err := h.invitationStore.GetByFormID(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get invitations", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get invitations"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, invitations)
+}
+
+// CreateInvitations generates a batch of invitations to the form. Their
+// tokens are only returned here.
+func (h *Handler) CreateInvitations(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	var req invitation.CreateRequest
+	err = internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	invitations, err := h.invitationStore.Create(r.Context(), id, req.Count, req.MaxUses, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, invitation.ErrInvalidCount) || errors.Is(err, invitation.ErrInvalidMaxUses) || errors.Is(err, invitation.ErrInvalidExpiry) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to create invitations", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to create invitations"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, invitations)
+}
+
+func (h *Handler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.Error("Invalid form ID", zap.String("id", idStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid form ID"))
+		return
+	}
+
+	if !h.authorize(w, r, id, collaborator.RoleEditor, auth.ScopeFormsWrite) {
+		return
+	}
+
+	invitationIDStr := r.PathValue("invitation_id")
+
+	invitationID, err := uuid.Parse(invitationIDStr)
+	if err != nil {
+		h.logger.Error("Invalid invitation ID", zap.String("invitation_id", invitationIDStr), zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError("Invalid invitation ID"))
+		return
+	}
+
+	err = h.invitationStore.Revoke(r.Context(), id, invitationID)
+	if err != nil {
+		if errors.Is(err, invitation.ErrInvitationNotFound) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusNotFound, internal.NewNotFoundError("Invitation not found"))
+			return
+		}
+		h.logger.Error("Failed to revoke invitation", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to revoke invitation"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
 }
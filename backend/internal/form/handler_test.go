@@ -0,0 +1,388 @@
+package form
+
+import (
+	"context"
+	"database-final-project/internal/answer"
+	"database-final-project/internal/auth"
+	"database-final-project/internal/collaborator"
+	"database-final-project/internal/export"
+	"database-final-project/internal/invitation"
+	"database-final-project/internal/submission"
+	"database-final-project/internal/summary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ownerQuerier answers the owner lookup of CheckPermission.
+type ownerQuerier struct {
+	Querier
+	owner pgtype.UUID
+}
+
+func (q ownerQuerier) GetOwnerID(ctx context.Context, id uuid.UUID) (pgtype.UUID, error) {
+	return q.owner, nil
+}
+
+type stubRoles map[uuid.UUID]collaborator.Role
+
+func (r stubRoles) GetRole(ctx context.Context, formID uuid.UUID, userID uuid.UUID) (collaborator.Role, error) {
+	role, ok := r[userID]
+	if !ok {
+		return "", collaborator.ErrCollaboratorNotFound
+	}
+	return role, nil
+}
+
+// stubStore checks permissions and lifecycles like the real service and
+// succeeds at everything else without touching a database. GetByID returns
+// form under the ID asked for.
+type stubStore struct {
+	*Service
+	form QuestionsForm
+}
+
+func (stubStore) List(ctx context.Context, params ListParams) (ListResponse, error) {
+	return ListResponse{Forms: []QuestionsForm{}}, nil
+}
+
+func (s stubStore) GetByID(ctx context.Context, id uuid.UUID) (QuestionsForm, error) {
+	form := s.form
+	form.FormID = id
+	return form, nil
+}
+
+func (stubStore) Create(ctx context.Context, ownerID uuid.UUID, title string, description string, lifecycle Lifecycle, respondents Respondents, questionRequest []QuestionRequest, sectionRequest []SectionRequest) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+func (stubStore) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (stubStore) Update(ctx context.Context, id uuid.UUID, title string, description *string, questionRequest []UpdateQuestionRequest, sectionRequest []UpdateSectionRequest) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+func (stubStore) Reorder(ctx context.Context, id uuid.UUID, orderRequest []OrderQuestionRequest, sectionOrder []OrderSectionRequest) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+func (stubStore) Duplicate(ctx context.Context, id uuid.UUID, ownerID uuid.UUID, title string) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+func (stubStore) SetLifecycle(ctx context.Context, id uuid.UUID, lifecycle Lifecycle) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+func (stubStore) SetRespondents(ctx context.Context, id uuid.UUID, respondents Respondents) (QuestionsForm, error) {
+	return QuestionsForm{}, nil
+}
+
+type stubSubmissions struct{}
+
+func (stubSubmissions) List(ctx context.Context, formID uuid.UUID, params submission.ListParams) (submission.ListResponse, error) {
+	return submission.ListResponse{}, nil
+}
+
+func (stubSubmissions) Get(ctx context.Context, formID uuid.UUID, id uuid.UUID) (submission.AnswersSubmission, error) {
+	return submission.AnswersSubmission{}, nil
+}
+
+func (stubSubmissions) Create(ctx context.Context, formID uuid.UUID, respondent submission.Respondent, idempotency submission.Idempotency, answers []answer.Request) (bool, error) {
+	return false, nil
+}
+
+func (stubSubmissions) Update(ctx context.Context, formID uuid.UUID, id uuid.UUID, answers []answer.Request) (submission.AnswersSubmission, error) {
+	return submission.AnswersSubmission{}, nil
+}
+
+func (stubSubmissions) Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	return nil
+}
+
+// missingFormSubmissions answers every submission as if the form were gone.
+type missingFormSubmissions struct {
+	stubSubmissions
+}
+
+func (missingFormSubmissions) Create(ctx context.Context, formID uuid.UUID, respondent submission.Respondent, idempotency submission.Idempotency, answers []answer.Request) (bool, error) {
+	return false, ErrFormNotFound
+}
+
+type stubSummaries struct{}
+
+func (stubSummaries) Get(ctx context.Context, formID uuid.UUID) (summary.Response, error) {
+	return summary.Response{}, nil
+}
+
+type stubExports struct{}
+
+func (stubExports) Export(ctx context.Context, w io.Writer, formID uuid.UUID, format export.Format, params submission.ListParams) error {
+	return nil
+}
+
+type stubCollaborators struct{}
+
+func (stubCollaborators) GetByFormID(ctx context.Context, formID uuid.UUID) ([]collaborator.Response, error) {
+	return []collaborator.Response{}, nil
+}
+
+func (stubCollaborators) Grant(ctx context.Context, formID uuid.UUID, username string, role collaborator.Role) (collaborator.Response, error) {
+	return collaborator.Response{}, nil
+}
+
+func (stubCollaborators) Revoke(ctx context.Context, formID uuid.UUID, userID uuid.UUID) error {
+	return nil
+}
+
+type stubInvitations struct{}
+
+func (stubInvitations) GetByFormID(ctx context.Context, formID uuid.UUID) ([]invitation.Response, error) {
+	return []invitation.Response{}, nil
+}
+
+func (stubInvitations) Create(ctx context.Context, formID uuid.UUID, count int32, maxUses int32, expiresAt *time.Time) ([]invitation.CreatedResponse, error) {
+	return []invitation.CreatedResponse{}, nil
+}
+
+func (stubInvitations) Check(ctx context.Context, formID uuid.UUID, token string) error {
+	return nil
+}
+
+func (stubInvitations) Revoke(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	return nil
+}
+
+// route is a form route as registered by RegisterRoutes. Public routes need
+// no user; the others need the scope and, on a form, the role.
+type route struct {
+	pattern string
+	path    string
+	body    string
+	public  bool
+	role    collaborator.Role
+	scope   auth.Scope
+	status  int
+}
+
+// recordingMux remembers the pattern of every route registered on it.
+type recordingMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (m *recordingMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
+}
+
+func formRoutes(formID uuid.UUID) []route {
+	form := "/api/forms/" + formID.String()
+	other := uuid.New().String()
+
+	return []route{
+		{pattern: "GET /api/forms", path: "/api/forms", scope: auth.ScopeFormsRead, status: http.StatusOK},
+		{pattern: "GET /api/forms/{id}", path: form, public: true, status: http.StatusOK},
+		{pattern: "POST /api/forms", path: "/api/forms", body: `{"title": "Survey"}`, scope: auth.ScopeFormsWrite, status: http.StatusCreated},
+		{pattern: "POST /api/forms/import", path: "/api/forms/import", body: `{"title": "Survey"}`, scope: auth.ScopeFormsWrite, status: http.StatusCreated},
+		{pattern: "GET /api/forms/{id}/definition", path: form + "/definition", role: collaborator.RoleViewer, scope: auth.ScopeFormsRead, status: http.StatusOK},
+		{pattern: "PUT /api/forms/{id}", path: form, body: `{"title": "Survey"}`, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusOK},
+		{pattern: "DELETE /api/forms/{id}", path: form, role: collaborator.RoleOwner, scope: auth.ScopeFormsWrite, status: http.StatusNoContent},
+		{pattern: "PUT /api/forms/{id}/order", path: form + "/order", body: `{"questions": []}`, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusOK},
+		{pattern: "POST /api/forms/{id}/duplicate", path: form + "/duplicate", role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusCreated},
+		{pattern: "PUT /api/forms/{id}/lifecycle", path: form + "/lifecycle", body: `{"status": "open"}`, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusOK},
+		{pattern: "PUT /api/forms/{id}/respondents", path: form + "/respondents", body: `{"respondent_identity": "anonymous"}`, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusOK},
+		{pattern: "GET /api/forms/{id}/collaborators", path: form + "/collaborators", role: collaborator.RoleViewer, scope: auth.ScopeFormsRead, status: http.StatusOK},
+		{pattern: "POST /api/forms/{id}/collaborators", path: form + "/collaborators", body: `{"username": "bob", "role": "viewer"}`, role: collaborator.RoleOwner, scope: auth.ScopeFormsWrite, status: http.StatusOK},
+		{pattern: "DELETE /api/forms/{id}/collaborators/{user_id}", path: form + "/collaborators/" + other, role: collaborator.RoleOwner, scope: auth.ScopeFormsWrite, status: http.StatusNoContent},
+		{pattern: "GET /api/forms/{id}/invitations", path: form + "/invitations", role: collaborator.RoleViewer, scope: auth.ScopeFormsRead, status: http.StatusOK},
+		{pattern: "POST /api/forms/{id}/invitations", path: form + "/invitations", body: `{"count": 1}`, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusCreated},
+		{pattern: "DELETE /api/forms/{id}/invitations/{invitation_id}", path: form + "/invitations/" + other, role: collaborator.RoleEditor, scope: auth.ScopeFormsWrite, status: http.StatusNoContent},
+		{pattern: "GET /api/forms/{id}/summary", path: form + "/summary", role: collaborator.RoleViewer, scope: auth.ScopeResponsesRead, status: http.StatusOK},
+		{pattern: "GET /api/forms/{id}/answers", path: form + "/answers", role: collaborator.RoleViewer, scope: auth.ScopeResponsesRead, status: http.StatusOK},
+		{pattern: "POST /api/forms/{id}/answers", path: form + "/answers", body: `{"answers": [{"question_id": "` + other + `", "answer_text": "Red"}]}`, public: true, status: http.StatusNoContent},
+		{pattern: "GET /api/forms/{id}/answers/export", path: form + "/answers/export", role: collaborator.RoleViewer, scope: auth.ScopeResponsesRead, status: http.StatusOK},
+		{pattern: "GET /api/forms/{id}/answers/{submission_id}", path: form + "/answers/" + other, role: collaborator.RoleViewer, scope: auth.ScopeResponsesRead, status: http.StatusOK},
+		{pattern: "PUT /api/forms/{id}/answers/{submission_id}", path: form + "/answers/" + other, body: `{"answers": [{"question_id": "` + other + `", "answer_text": "Red"}]}`, role: collaborator.RoleEditor, scope: auth.ScopeResponsesWrite, status: http.StatusOK},
+		{pattern: "DELETE /api/forms/{id}/answers/{submission_id}", path: form + "/answers/" + other, role: collaborator.RoleEditor, scope: auth.ScopeResponsesWrite, status: http.StatusNoContent},
+	}
+}
+
+func TestRoutePermissions(t *testing.T) {
+	formID := uuid.New()
+	owner := uuid.New()
+	coOwner := uuid.New()
+	editor := uuid.New()
+	viewer := uuid.New()
+	stranger := uuid.New()
+
+	roles := stubRoles{
+		coOwner: collaborator.RoleOwner,
+		editor:  collaborator.RoleEditor,
+		viewer:  collaborator.RoleViewer,
+	}
+	logger := zap.NewNop()
+	service := NewService(logger, ownerQuerier{owner: pgtype.UUID{Bytes: owner, Valid: true}}, nil, nil, nil, nil, roles, nil)
+	handler := NewHandler(logger, stubStore{Service: service}, stubSubmissions{}, stubSummaries{}, stubExports{}, stubCollaborators{}, stubInvitations{})
+
+	routes := formRoutes(formID)
+	mux := &recordingMux{ServeMux: http.NewServeMux()}
+	handler.RegisterRoutes(mux)
+
+	tested := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		tested[route.pattern] = true
+	}
+	for _, pattern := range mux.patterns {
+		if !tested[pattern] {
+			t.Errorf("route %q is registered but has no permission case", pattern)
+		}
+	}
+	if len(mux.patterns) != len(routes) {
+		t.Errorf("registered %d routes, want %d", len(mux.patterns), len(routes))
+	}
+
+	users := []struct {
+		name string
+		user *auth.User
+		role collaborator.Role
+	}{
+		{name: "anonymous"},
+		{name: "stranger", user: &auth.User{ID: stranger}},
+		{name: "viewer", user: &auth.User{ID: viewer}, role: collaborator.RoleViewer},
+		{name: "editor", user: &auth.User{ID: editor}, role: collaborator.RoleEditor},
+		{name: "co-owner", user: &auth.User{ID: coOwner}, role: collaborator.RoleOwner},
+		{name: "owner", user: &auth.User{ID: owner}, role: collaborator.RoleOwner},
+		{name: "owner key with forms:read", user: &auth.User{ID: owner, Scopes: []auth.Scope{auth.ScopeFormsRead}}, role: collaborator.RoleOwner},
+		{name: "owner key with forms:write", user: &auth.User{ID: owner, Scopes: []auth.Scope{auth.ScopeFormsWrite}}, role: collaborator.RoleOwner},
+		{name: "owner key with responses:read", user: &auth.User{ID: owner, Scopes: []auth.Scope{auth.ScopeResponsesRead}}, role: collaborator.RoleOwner},
+		{name: "owner key with responses:write", user: &auth.User{ID: owner, Scopes: []auth.Scope{auth.ScopeResponsesWrite}}, role: collaborator.RoleOwner},
+		{name: "owner key with every scope", user: &auth.User{ID: owner, Scopes: auth.Scopes}, role: collaborator.RoleOwner},
+		{name: "viewer key with every scope", user: &auth.User{ID: viewer, Scopes: auth.Scopes}, role: collaborator.RoleViewer},
+		{name: "editor key with forms:read", user: &auth.User{ID: editor, Scopes: []auth.Scope{auth.ScopeFormsRead}}, role: collaborator.RoleEditor},
+	}
+
+	for _, route := range routes {
+		for _, u := range users {
+			t.Run(route.pattern+" as "+u.name, func(t *testing.T) {
+				want := route.status
+				switch {
+				case route.public:
+				case u.user == nil:
+					want = http.StatusUnauthorized
+				case !u.user.HasScope(route.scope):
+					want = http.StatusForbidden
+				case route.role != "" && !u.role.Allows(route.role):
+					want = http.StatusForbidden
+				}
+
+				method, _, _ := strings.Cut(route.pattern, " ")
+				r := httptest.NewRequest(method, route.path, strings.NewReader(route.body))
+				if u.user != nil {
+					r = r.WithContext(auth.WithUser(r.Context(), *u.user))
+				}
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, r)
+
+				if w.Code != want {
+					t.Errorf("status = %d, want %d; body: %s", w.Code, want, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestGetByIDHidesFormsNotTakingResponses(t *testing.T) {
+	formID := uuid.New()
+	owner := uuid.New()
+	viewer := uuid.New()
+	stranger := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	logger := zap.NewNop()
+	service := NewService(logger, ownerQuerier{owner: pgtype.UUID{Bytes: owner, Valid: true}}, nil, nil, nil, nil, stubRoles{viewer: collaborator.RoleViewer}, nil)
+
+	users := []struct {
+		name         string
+		user         *auth.User
+		collaborator bool
+	}{
+		{name: "anonymous"},
+		{name: "stranger", user: &auth.User{ID: stranger}},
+		{name: "viewer", user: &auth.User{ID: viewer}, collaborator: true},
+		{name: "owner", user: &auth.User{ID: owner}, collaborator: true},
+		{name: "owner key without forms:read", user: &auth.User{ID: owner, Scopes: []auth.Scope{auth.ScopeResponsesRead}}},
+	}
+
+	tests := []struct {
+		name      string
+		lifecycle Lifecycle
+		open      bool
+	}{
+		{name: "open", lifecycle: Lifecycle{Status: FormStatusOpen, ClosesAt: &future}, open: true},
+		{name: "draft", lifecycle: Lifecycle{Status: FormStatusDraft}},
+		{name: "closed", lifecycle: Lifecycle{Status: FormStatusClosed}},
+		{name: "not open yet", lifecycle: Lifecycle{Status: FormStatusOpen, OpensAt: &future}},
+		{name: "past its closing time", lifecycle: Lifecycle{Status: FormStatusOpen, ClosesAt: &past}},
+	}
+
+	for _, tt := range tests {
+		for _, u := range users {
+			t.Run(tt.name+" as "+u.name, func(t *testing.T) {
+				form := QuestionsForm{SummaryForm: SummaryForm{OwnerID: &owner, Title: "Survey", Lifecycle: tt.lifecycle}}
+				handler := NewHandler(logger, stubStore{Service: service, form: form}, stubSubmissions{}, stubSummaries{}, stubExports{}, stubCollaborators{}, stubInvitations{})
+
+				r := httptest.NewRequest(http.MethodGet, "/api/forms/"+formID.String(), nil)
+				r.SetPathValue("id", formID.String())
+				if u.user != nil {
+					r = r.WithContext(auth.WithUser(r.Context(), *u.user))
+				}
+				w := httptest.NewRecorder()
+				handler.GetByID(w, r)
+
+				want := http.StatusOK
+				if !u.collaborator && !tt.open {
+					want = http.StatusNotFound
+				}
+				if w.Code != want {
+					t.Fatalf("status = %d, want %d; body: %s", w.Code, want, w.Body.String())
+				}
+				if want != http.StatusOK {
+					return
+				}
+
+				showsOwner := strings.Contains(w.Body.String(), `"owner_id"`)
+				if showsOwner != u.collaborator {
+					t.Errorf("response shows owner_id = %t, want %t", showsOwner, u.collaborator)
+				}
+			})
+		}
+	}
+}
+
+func TestCreateAnswerForMissingForm(t *testing.T) {
+	formID := uuid.New()
+
+	// The form is only looked up while the submission is created, so the
+	// handler needs no form store.
+	handler := NewHandler(zap.NewNop(), nil, missingFormSubmissions{}, stubSummaries{}, stubExports{}, stubCollaborators{}, stubInvitations{})
+
+	r := httptest.NewRequest(http.MethodPost, "/api/forms/"+formID.String()+"/answers", strings.NewReader(`{"answers": []}`))
+	r.SetPathValue("id", formID.String())
+	w := httptest.NewRecorder()
+	handler.CreateAnswer(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusNotFound, w.Body.String())
+	}
+}
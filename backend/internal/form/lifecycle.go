@@ -0,0 +1,78 @@
+package form
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// normalize checks the lifecycle and fills in the default status, open.
+func (l Lifecycle) normalize() (Lifecycle, error) {
+	switch l.Status {
+	case "":
+		l.Status = FormStatusOpen
+	case FormStatusDraft, FormStatusOpen, FormStatusClosed:
+	default:
+		return Lifecycle{}, ErrInvalidStatus
+	}
+
+	if l.OpensAt != nil && l.ClosesAt != nil && !l.ClosesAt.After(*l.OpensAt) {
+		return Lifecycle{}, ErrInvalidSchedule
+	}
+	if l.MaxResponses != nil && *l.MaxResponses < 1 {
+		return Lifecycle{}, ErrInvalidMaxResponses
+	}
+
+	return l, nil
+}
+
+// check reports why a form with this lifecycle and the given number of
+// responses turns away a submission made at now, or nil when it accepts it.
+func (l Lifecycle) check(now time.Time, responses int64) error {
+	switch l.Status {
+	case FormStatusDraft:
+		return ErrFormDraft
+	case FormStatusClosed:
+		return ErrFormClosed
+	}
+
+	if l.OpensAt != nil && now.Before(*l.OpensAt) {
+		return ErrFormNotOpenYet
+	}
+	if l.ClosesAt != nil && !now.Before(*l.ClosesAt) {
+		return ErrFormDeadlinePassed
+	}
+	if l.MaxResponses != nil && responses >= int64(*l.MaxResponses) {
+		return ErrResponseLimitReached
+	}
+
+	return nil
+}
+
+func toLifecycle(form Form) Lifecycle {
+	lifecycle := Lifecycle{Status: form.Status}
+	if form.OpensAt.Valid {
+		lifecycle.OpensAt = &form.OpensAt.Time
+	}
+	if form.ClosesAt.Valid {
+		lifecycle.ClosesAt = &form.ClosesAt.Time
+	}
+	if form.MaxResponses.Valid {
+		lifecycle.MaxResponses = &form.MaxResponses.Int32
+	}
+	return lifecycle
+}
+
+func nullTime(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func nullInt32(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *i, Valid: true}
+}
@@ -0,0 +1,103 @@
+package form
+
+import (
+	"context"
+	"database-final-project/internal/collaborator"
+	"database-final-project/internal/database"
+	"database-final-project/internal/invitation"
+	"database-final-project/internal/options"
+	"database-final-project/internal/question"
+	"database-final-project/internal/section"
+	"database-final-project/internal/version"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// newTestDB connects to the Postgres at DATABASE_URL, migrated to the latest
+// schema, and skips the test when it is not set. Every query is reported to
+// tracer when one is given.
+func newTestDB(tb testing.TB, tracer pgx.QueryTracer) *database.DB {
+	tb.Helper()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		tb.Skip("DATABASE_URL is not set")
+	}
+
+	err := database.MigrationUp("file://../database/migrations", databaseURL, zap.NewNop())
+	if err != nil {
+		tb.Fatalf("migrate: %v", err)
+	}
+
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		tb.Fatalf("parse DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.Tracer = tracer
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		tb.Fatalf("connect: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+
+	return database.NewDB(pool)
+}
+
+// newTestUser creates a user to own the forms of a test, and removes both
+// once the test is done.
+func newTestUser(tb testing.TB, db *database.DB) uuid.UUID {
+	tb.Helper()
+	ctx := context.Background()
+
+	var id uuid.UUID
+	err := db.QueryRow(ctx, "INSERT INTO users (username, password_hash) VALUES ($1, '') RETURNING id", "test-"+uuid.NewString()[:8]).Scan(&id)
+	if err != nil {
+		tb.Fatalf("create user: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		_, err := db.Exec(ctx, "DELETE FROM forms WHERE owner_id = $1", id)
+		if err != nil {
+			tb.Errorf("delete forms: %v", err)
+		}
+		_, err = db.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
+		if err != nil {
+			tb.Errorf("delete user: %v", err)
+		}
+	})
+
+	return id
+}
+
+// newTestService wires a Service to db the way cmd/backend does, with
+// optionQuerier in place of the options queries.
+func newTestService(db *database.DB, optionQuerier options.Querier) *Service {
+	logger := zap.NewNop()
+	questionService := question.NewService(logger, question.New(db), options.NewService(logger, optionQuerier))
+	sectionService := section.NewService(logger, section.New(db))
+	versionService := version.NewService(logger, version.New(db))
+	collaboratorService := collaborator.NewService(logger, collaborator.New(db))
+	invitationService := invitation.NewService(logger, invitation.New(db))
+
+	return NewService(logger, New(db), db, questionService, sectionService, versionService, collaboratorService, invitationService)
+}
+
+// queryCounter counts the queries sent to Postgres, each one a round trip.
+type queryCounter struct {
+	queries atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	c.queries.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
@@ -1,14 +1,107 @@
--- name: GetAll :many
-SELECT * FROM forms;
+-- name: ListByCreatedAt :many
+SELECT *
+FROM forms
+WHERE title ILIKE '%' || @search::text || '%'
+  AND (owner_id = @user_id::uuid OR EXISTS (SELECT 1
+                                              FROM form_collaborators c
+                                              WHERE c.form_id = forms.id
+                                                AND c.user_id = @user_id::uuid))
+  AND (NOT @has_cursor::bool
+    OR (NOT @descending::bool AND (created_at, id) > (@cursor_created_at::timestamptz, @cursor_id::uuid))
+    OR (@descending::bool AND (created_at, id) < (@cursor_created_at::timestamptz, @cursor_id::uuid)))
+ORDER BY CASE WHEN @descending::bool THEN created_at END DESC,
+         CASE WHEN @descending::bool THEN id END DESC,
+         created_at ASC,
+         id ASC
+LIMIT @page_size;
+
+-- name: ListByUpdatedAt :many
+SELECT *
+FROM forms
+WHERE title ILIKE '%' || @search::text || '%'
+  AND (owner_id = @user_id::uuid OR EXISTS (SELECT 1
+                                              FROM form_collaborators c
+                                              WHERE c.form_id = forms.id
+                                                AND c.user_id = @user_id::uuid))
+  AND (NOT @has_cursor::bool
+    OR (NOT @descending::bool AND (updated_at, id) > (@cursor_updated_at::timestamptz, @cursor_id::uuid))
+    OR (@descending::bool AND (updated_at, id) < (@cursor_updated_at::timestamptz, @cursor_id::uuid)))
+ORDER BY CASE WHEN @descending::bool THEN updated_at END DESC,
+         CASE WHEN @descending::bool THEN id END DESC,
+         updated_at ASC,
+         id ASC
+LIMIT @page_size;
+
+-- name: ListByTitle :many
+SELECT *
+FROM forms
+WHERE title ILIKE '%' || @search::text || '%'
+  AND (owner_id = @user_id::uuid OR EXISTS (SELECT 1
+                                              FROM form_collaborators c
+                                              WHERE c.form_id = forms.id
+                                                AND c.user_id = @user_id::uuid))
+  AND (NOT @has_cursor::bool
+    OR (NOT @descending::bool AND (title, id) > (@cursor_title::text, @cursor_id::uuid))
+    OR (@descending::bool AND (title, id) < (@cursor_title::text, @cursor_id::uuid)))
+ORDER BY CASE WHEN @descending::bool THEN title END DESC,
+         CASE WHEN @descending::bool THEN id END DESC,
+         title ASC,
+         id ASC
+LIMIT @page_size;
+
+-- name: Count :one
+SELECT COUNT(*)
+FROM forms
+WHERE title ILIKE '%' || @search::text || '%'
+  AND (owner_id = @user_id::uuid OR EXISTS (SELECT 1
+                                              FROM form_collaborators c
+                                              WHERE c.form_id = forms.id
+                                                AND c.user_id = @user_id::uuid));
 
 -- name: GetByID :one
 SELECT * FROM forms WHERE id = $1;
 
+-- name: GetOwnerID :one
+SELECT owner_id FROM forms WHERE id = $1;
+
+-- name: GetByIDForUpdate :one
+SELECT * FROM forms WHERE id = $1 FOR NO KEY UPDATE;
+
+-- name: CountSubmissions :one
+SELECT COUNT(*) FROM submissions WHERE form_id = $1;
+
 -- name: Create :one
-INSERT INTO forms (title) VALUES ($1) RETURNING *;
+INSERT INTO forms (title, description, status, opens_at, closes_at, max_responses, owner_id, respondent_identity,
+                   one_response_per_respondent, invite_only)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
 
 -- name: Update :one
-UPDATE forms SET title = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING *;
+UPDATE forms
+SET title       = @title,
+    description = COALESCE(sqlc.narg(description)::text, description),
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: UpdateLifecycle :one
+UPDATE forms
+SET status        = @status,
+    opens_at      = sqlc.narg(opens_at),
+    closes_at     = sqlc.narg(closes_at),
+    max_responses = sqlc.narg(max_responses),
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: UpdateRespondents :one
+UPDATE forms
+SET respondent_identity         = @respondent_identity,
+    one_response_per_respondent = @one_response_per_respondent,
+    invite_only                 = @invite_only,
+    updated_at                  = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
 
 -- name: Delete :exec
-DELETE FROM forms WHERE id = $1;
\ No newline at end of file
+DELETE FROM forms WHERE id = $1;
@@ -0,0 +1,73 @@
+package form
+
+import (
+	"database-final-project/internal/submission"
+	"net/mail"
+	"strings"
+)
+
+// maxEmailLength is the longest address respondent_email holds.
+const maxEmailLength = 320
+
+// normalize checks the settings and fills in the default identity, anonymous.
+// Forms limited to one response need the identity required, as they only
+// take signed-in users.
+func (r Respondents) normalize() (Respondents, error) {
+	switch r.Identity {
+	case "":
+		r.Identity = RespondentIdentityAnonymous
+	case RespondentIdentityAnonymous, RespondentIdentityOptional, RespondentIdentityRequired:
+	default:
+		return Respondents{}, ErrInvalidIdentity
+	}
+
+	if r.OneResponse && r.Identity != RespondentIdentityRequired {
+		return Respondents{}, ErrOneResponseNeedsIdentity
+	}
+
+	return r, nil
+}
+
+// check returns what a form with these settings records about the
+// respondent: nothing for anonymous forms, otherwise the signed-in user and
+// the email they gave. Forms limited to one response only take signed-in
+// users, since anyone can type any email, and also get the key the unique
+// constraint on submissions works on.
+func (r Respondents) check(respondent submission.Respondent) (submission.Respondent, error) {
+	if r.Identity == RespondentIdentityAnonymous {
+		return submission.Respondent{}, nil
+	}
+
+	email := strings.TrimSpace(respondent.Email)
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email || len(email) > maxEmailLength {
+			return submission.Respondent{}, ErrInvalidEmail
+		}
+	}
+
+	recorded := submission.Respondent{
+		UserID: respondent.UserID,
+		Email:  email,
+	}
+	if r.OneResponse {
+		if recorded.UserID == nil {
+			return submission.Respondent{}, ErrSignInRequired
+		}
+		recorded.Key = "user:" + recorded.UserID.String()
+	}
+
+	if r.Identity == RespondentIdentityRequired && recorded.UserID == nil && recorded.Email == "" {
+		return submission.Respondent{}, ErrIdentityRequired
+	}
+
+	return recorded, nil
+}
+
+func toRespondents(form Form) Respondents {
+	return Respondents{
+		Identity:    form.RespondentIdentity,
+		OneResponse: form.OneResponsePerRespondent,
+		InviteOnly:  form.InviteOnly,
+	}
+}
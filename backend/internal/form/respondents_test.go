@@ -0,0 +1,106 @@
+package form
+
+import (
+	"database-final-project/internal/submission"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRespondentsNormalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		respondents Respondents
+		want        Respondents
+		wantErr     error
+	}{
+		{name: "default identity", want: Respondents{Identity: RespondentIdentityAnonymous}},
+		{name: "unknown identity", respondents: Respondents{Identity: "secret"}, wantErr: ErrInvalidIdentity},
+		{name: "one response when anonymous", respondents: Respondents{OneResponse: true}, wantErr: ErrOneResponseNeedsIdentity},
+		{name: "one response when optional", respondents: Respondents{Identity: RespondentIdentityOptional, OneResponse: true}, wantErr: ErrOneResponseNeedsIdentity},
+		{
+			name:        "one response when required",
+			respondents: Respondents{Identity: RespondentIdentityRequired, OneResponse: true},
+			want:        Respondents{Identity: RespondentIdentityRequired, OneResponse: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.respondents.normalize()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalize() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondentsCheck(t *testing.T) {
+	userID := uuid.New()
+	oneResponse := Respondents{Identity: RespondentIdentityRequired, OneResponse: true}
+
+	tests := []struct {
+		name        string
+		respondents Respondents
+		respondent  submission.Respondent
+		want        submission.Respondent
+		wantErr     error
+	}{
+		{
+			name:        "anonymous form records nothing",
+			respondents: Respondents{Identity: RespondentIdentityAnonymous},
+			respondent:  submission.Respondent{UserID: &userID, Email: "ann@example.com"},
+		},
+		{
+			name:        "required identity takes an email",
+			respondents: Respondents{Identity: RespondentIdentityRequired},
+			respondent:  submission.Respondent{Email: " ann@example.com "},
+			want:        submission.Respondent{Email: "ann@example.com"},
+		},
+		{
+			name:        "required identity without either",
+			respondents: Respondents{Identity: RespondentIdentityRequired},
+			wantErr:     ErrIdentityRequired,
+		},
+		{
+			name:        "invalid email",
+			respondents: Respondents{Identity: RespondentIdentityOptional},
+			respondent:  submission.Respondent{Email: "ann"},
+			wantErr:     ErrInvalidEmail,
+		},
+		{
+			name:        "one response keys on the user",
+			respondents: oneResponse,
+			respondent:  submission.Respondent{UserID: &userID, Email: "ann@example.com"},
+			want:        submission.Respondent{UserID: &userID, Email: "ann@example.com", Key: "user:" + userID.String()},
+		},
+		{
+			name:        "one response turns away an email",
+			respondents: oneResponse,
+			respondent:  submission.Respondent{Email: "ann@example.com"},
+			wantErr:     ErrSignInRequired,
+		},
+		{
+			name:        "one response turns away anonymous respondents",
+			respondents: oneResponse,
+			wantErr:     ErrSignInRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.respondents.check(tt.respondent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("check() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("check() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,27 @@
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
+CREATE TYPE form_status AS ENUM ('draft', 'open', 'closed');
+CREATE TYPE respondent_identity AS ENUM ('anonymous', 'optional', 'required');
+
 CREATE TABLE IF NOT EXISTS forms (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     title VARCHAR(255) NOT NULL,
-    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    description TEXT NOT NULL DEFAULT '',
+    status form_status NOT NULL DEFAULT 'open',
+    opens_at TIMESTAMPTZ,
+    closes_at TIMESTAMPTZ,
+    max_responses INTEGER CHECK (max_responses > 0),
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    respondent_identity respondent_identity NOT NULL DEFAULT 'anonymous',
+    one_response_per_respondent BOOLEAN NOT NULL DEFAULT false,
+    invite_only BOOLEAN NOT NULL DEFAULT false,
+    CONSTRAINT forms_schedule_check CHECK (closes_at > opens_at),
+    CONSTRAINT forms_one_response_check CHECK (NOT one_response_per_respondent OR respondent_identity = 'required')
+);
+
+CREATE INDEX IF NOT EXISTS forms_created_at_id_idx ON forms (created_at, id);
+CREATE INDEX IF NOT EXISTS forms_updated_at_id_idx ON forms (updated_at, id);
+CREATE INDEX IF NOT EXISTS forms_title_id_idx ON forms (title, id);
+CREATE INDEX IF NOT EXISTS forms_owner_id_idx ON forms (owner_id);
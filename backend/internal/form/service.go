@@ -2,64 +2,298 @@ package form
 
 import (
 	"context"
+	"database-final-project/internal"
+	"database-final-project/internal/collaborator"
+	"database-final-project/internal/invitation"
+	"database-final-project/internal/options"
 	"database-final-project/internal/question"
+	"database-final-project/internal/section"
+	"database-final-project/internal/submission"
+	"database-final-project/internal/version"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
 type Querier interface {
-	GetAll(ctx context.Context) ([]Form, error)
+	ListByCreatedAt(ctx context.Context, arg ListByCreatedAtParams) ([]Form, error)
+	ListByUpdatedAt(ctx context.Context, arg ListByUpdatedAtParams) ([]Form, error)
+	ListByTitle(ctx context.Context, arg ListByTitleParams) ([]Form, error)
+	Count(ctx context.Context, arg CountParams) (int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (Form, error)
-	Create(ctx context.Context, title string) (Form, error)
+	GetOwnerID(ctx context.Context, id uuid.UUID) (pgtype.UUID, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (Form, error)
+	CountSubmissions(ctx context.Context, formID uuid.UUID) (int64, error)
+	Create(ctx context.Context, arg CreateParams) (Form, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	Update(ctx context.Context, param UpdateParams) (Form, error)
+	UpdateLifecycle(ctx context.Context, arg UpdateLifecycleParams) (Form, error)
+	UpdateRespondents(ctx context.Context, arg UpdateRespondentsParams) (Form, error)
 }
 
 type questionStore interface {
 	GetByFormID(ctx context.Context, formID uuid.UUID) ([]question.OptionsQuestion, error)
-	Create(ctx context.Context, formID uuid.UUID, questionText string, questionType string, isRequired bool, optionsReq []string) (question.OptionsQuestion, error)
+	GetByFormIDs(ctx context.Context, formIDs []uuid.UUID) (map[uuid.UUID][]question.OptionsQuestion, error)
+	Create(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, questionText string, description string, questionType string, isRequired bool, position int32, config *question.Config, validation *question.Validation, optionsReq []string) (question.OptionsQuestion, error)
+	Update(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, id uuid.UUID, questionText string, description string, questionType string, isRequired bool, position int32, config *question.Config, validation *question.Validation, optionsReq []options.Request) (question.OptionsQuestion, error)
+	Reorder(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, id uuid.UUID, position int32, optionIDs []uuid.UUID) error
+	SetLogic(ctx context.Context, formID uuid.UUID, id uuid.UUID, logic *question.Logic) error
+	Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error
+}
+
+type sectionStore interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]section.Response, error)
+	GetByFormIDs(ctx context.Context, formIDs []uuid.UUID) (map[uuid.UUID][]section.Response, error)
+	Create(ctx context.Context, formID uuid.UUID, title string, description string, position int32) (section.Response, error)
+	Update(ctx context.Context, formID uuid.UUID, id uuid.UUID, title string, description string, position int32) (section.Response, error)
+	UpdatePosition(ctx context.Context, formID uuid.UUID, id uuid.UUID, position int32) error
+	Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error
+}
+
+type versionStore interface {
+	Publish(ctx context.Context, formID uuid.UUID, questions []question.OptionsQuestion) (version.Response, error)
+}
+
+type roleStore interface {
+	GetRole(ctx context.Context, formID uuid.UUID, userID uuid.UUID) (collaborator.Role, error)
+}
+
+type invitationConsumer interface {
+	Consume(ctx context.Context, formID uuid.UUID, token string) (uuid.UUID, error)
+}
+
+type transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Service struct {
-	logger        *zap.Logger
-	querier       Querier
-	questionStore questionStore
+	logger             *zap.Logger
+	querier            Querier
+	transactor         transactor
+	questionStore      questionStore
+	sectionStore       sectionStore
+	versionStore       versionStore
+	roleStore          roleStore
+	invitationConsumer invitationConsumer
 }
 
-func NewService(logger *zap.Logger, querier Querier, questionStore questionStore) *Service {
+func NewService(logger *zap.Logger, querier Querier, transactor transactor, questionStore questionStore, sectionStore sectionStore, versionStore versionStore, roleStore roleStore, invitationConsumer invitationConsumer) *Service {
 	return &Service{
-		logger:        logger,
-		querier:       querier,
-		questionStore: questionStore,
+		logger:             logger,
+		querier:            querier,
+		transactor:         transactor,
+		questionStore:      questionStore,
+		sectionStore:       sectionStore,
+		versionStore:       versionStore,
+		roleStore:          roleStore,
+		invitationConsumer: invitationConsumer,
 	}
 }
 
-func (s *Service) GetAll(ctx context.Context) ([]QuestionsForm, error) {
-	forms, err := s.querier.GetAll(ctx)
+// List returns one page of the forms of params.UserID matching params.
+// Questions are loaded for the whole page at once, or skipped entirely in
+// summary mode.
+func (s *Service) List(ctx context.Context, params ListParams) (ListResponse, error) {
+	var c cursor
+	if params.Cursor != "" {
+		var err error
+		c, err = decodeCursor(params.Cursor)
+		if err != nil {
+			return ListResponse{}, err
+		}
+		if c.Sort != params.Sort || c.Descending != params.Descending {
+			return ListResponse{}, internal.ErrInvalidCursor
+		}
+	}
+
+	search := internal.EscapeLike(params.Search)
+
+	// Fetch one extra row to find out whether there is a next page.
+	forms, err := s.list(ctx, params, search, c, params.Limit+1)
 	if err != nil {
-		return nil, err
+		return ListResponse{}, err
+	}
+
+	total, err := s.querier.Count(ctx, CountParams{
+		Search: search,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return ListResponse{}, err
 	}
 
-	var questionsForms []QuestionsForm
-	for _, form := range forms {
-		q, err := s.questionStore.GetByFormID(ctx, form.ID)
+	var nextCursor string
+	if len(forms) > int(params.Limit) {
+		forms = forms[:params.Limit]
+		nextCursor = newCursor(params, forms[len(forms)-1]).encode()
+	}
+
+	var questionsByForm map[uuid.UUID][]question.OptionsQuestion
+	var sectionsByForm map[uuid.UUID][]section.Response
+	if !params.Summary && len(forms) > 0 {
+		formIDs := make([]uuid.UUID, len(forms))
+		for i, form := range forms {
+			formIDs[i] = form.ID
+		}
+
+		questionsByForm, err = s.questionStore.GetByFormIDs(ctx, formIDs)
 		if err != nil {
-			return nil, err
+			return ListResponse{}, err
+		}
+
+		sectionsByForm, err = s.sectionStore.GetByFormIDs(ctx, formIDs)
+		if err != nil {
+			return ListResponse{}, err
+		}
+	}
+
+	questionsForms := make([]QuestionsForm, len(forms))
+	for i, form := range forms {
+		questionsForms[i] = toQuestionsForm(form, questionsByForm[form.ID], sectionsByForm[form.ID])
+	}
+
+	return ListResponse{
+		Forms:      questionsForms,
+		NextCursor: nextCursor,
+		Total:      total,
+	}, nil
+}
+
+func (s *Service) list(ctx context.Context, params ListParams, search string, c cursor, pageSize int32) ([]Form, error) {
+	hasCursor := params.Cursor != ""
+
+	switch params.Sort {
+	case SortUpdatedAt, SortCreatedAt:
+		var cursorTime pgtype.Timestamptz
+		if hasCursor {
+			t, err := time.Parse(time.RFC3339Nano, c.Value)
+			if err != nil {
+				return nil, internal.ErrInvalidCursor
+			}
+			cursorTime = pgtype.Timestamptz{Time: t, Valid: true}
 		}
-		questionsForms = append(questionsForms, QuestionsForm{
-			FormID:    form.ID,
-			Title:     form.Title,
-			Questions: q,
+
+		if params.Sort == SortUpdatedAt {
+			return s.querier.ListByUpdatedAt(ctx, ListByUpdatedAtParams{
+				Search:          search,
+				UserID:          params.UserID,
+				HasCursor:       hasCursor,
+				Descending:      params.Descending,
+				CursorUpdatedAt: cursorTime,
+				CursorID:        c.ID,
+				PageSize:        pageSize,
+			})
+		}
+		return s.querier.ListByCreatedAt(ctx, ListByCreatedAtParams{
+			Search:          search,
+			UserID:          params.UserID,
+			HasCursor:       hasCursor,
+			Descending:      params.Descending,
+			CursorCreatedAt: cursorTime,
+			CursorID:        c.ID,
+			PageSize:        pageSize,
+		})
+	case SortTitle:
+		return s.querier.ListByTitle(ctx, ListByTitleParams{
+			Search:      search,
+			UserID:      params.UserID,
+			HasCursor:   hasCursor,
+			Descending:  params.Descending,
+			CursorTitle: c.Value,
+			CursorID:    c.ID,
+			PageSize:    pageSize,
 		})
+	default:
+		return nil, ErrInvalidSort
+	}
+}
+
+func newCursor(params ListParams, last Form) cursor {
+	c := cursor{
+		Sort:       params.Sort,
+		Descending: params.Descending,
+		ID:         last.ID,
+	}
+
+	switch params.Sort {
+	case SortCreatedAt:
+		c.Value = last.CreatedAt.Time.Format(time.RFC3339Nano)
+	case SortUpdatedAt:
+		c.Value = last.UpdatedAt.Time.Format(time.RFC3339Nano)
+	case SortTitle:
+		c.Value = last.Title
 	}
 
-	return questionsForms, nil
+	return c
+}
+
+// toQuestionsForm builds the response for a form, grouping the questions by
+// section when the form has any.
+func toQuestionsForm(form Form, questions []question.OptionsQuestion, sections []section.Response) QuestionsForm {
+	var formSections []QuestionsSection
+	if len(sections) > 0 {
+		formSections = make([]QuestionsSection, len(sections))
+		positions := make(map[uuid.UUID]int, len(sections))
+		for i, s := range sections {
+			formSections[i] = QuestionsSection{
+				SectionID:   s.SectionID,
+				Title:       s.Title,
+				Description: s.Description,
+				Position:    s.Position,
+				Questions:   []question.OptionsQuestion{},
+			}
+			positions[s.SectionID] = i
+		}
+		for _, q := range questions {
+			if q.SectionID == nil {
+				continue
+			}
+			if i, ok := positions[*q.SectionID]; ok {
+				formSections[i].Questions = append(formSections[i].Questions, q)
+			}
+		}
+	}
+
+	if questions == nil {
+		questions = []question.OptionsQuestion{}
+	}
+
+	return QuestionsForm{
+		SummaryForm: SummaryForm{
+			FormID:      form.ID,
+			Title:       form.Title,
+			Description: form.Description,
+			OwnerID:     ownerIDPtr(form.OwnerID),
+			Lifecycle:   toLifecycle(form),
+			Respondents: toRespondents(form),
+			CreatedAt:   form.CreatedAt.Time,
+			UpdatedAt:   form.UpdatedAt.Time,
+		},
+		Questions: questions,
+		Sections:  formSections,
+	}
+}
+
+func ownerIDPtr(owner pgtype.UUID) *uuid.UUID {
+	if !owner.Valid {
+		return nil
+	}
+	id := uuid.UUID(owner.Bytes)
+	return &id
 }
 
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (QuestionsForm, error) {
 	forms, err := s.querier.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return QuestionsForm{}, ErrFormNotFound
+		}
 		return QuestionsForm{}, err
 	}
 
@@ -68,54 +302,653 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (QuestionsForm, err
 		return QuestionsForm{}, err
 	}
 
-	return QuestionsForm{
-		FormID:    forms.ID,
-		Title:     forms.Title,
-		Questions: questions,
-	}, nil
+	sections, err := s.sectionStore.GetByFormID(ctx, forms.ID)
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	return toQuestionsForm(forms, questions, sections), nil
 }
 
-func (s *Service) Create(ctx context.Context, title string, questionRequest []QuestionRequest) (QuestionsForm, error) {
-	form, err := s.querier.Create(ctx, title)
+// Create inserts the form together with all of its sections, questions and
+// options in one transaction, so a failure at any step leaves no partial form
+// behind. The questions are given either as a flat list or grouped by
+// section; question logic refers to them by their index across all sections.
+// Logic is applied once every question exists, so it can refer to questions
+// and options created by the same request. The question tree is published as
+// the first version of the form. A lifecycle without a status opens the form
+// right away; responses are anonymous unless the respondent settings say
+// otherwise.
+func (s *Service) Create(ctx context.Context, ownerID uuid.UUID, title string, description string, lifecycle Lifecycle, respondents Respondents, questionRequest []QuestionRequest, sectionRequest []SectionRequest) (QuestionsForm, error) {
+	if len(questionRequest) > 0 && len(sectionRequest) > 0 {
+		return QuestionsForm{}, ErrQuestionsAndSections
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return QuestionsForm{}, ErrDescriptionTooLong
+	}
+	lifecycle, err := lifecycle.normalize()
 	if err != nil {
 		return QuestionsForm{}, err
 	}
+	respondents, err = respondents.normalize()
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	var sectionIndexes []int
+	if len(sectionRequest) > 0 {
+		questionRequest, sectionIndexes = flattenSections(sectionRequest)
+	}
 
+	var form Form
 	questions := make([]question.OptionsQuestion, len(questionRequest))
+	sections := make([]section.Response, len(sectionRequest))
+
+	err = s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		form, err = s.querier.Create(ctx, CreateParams{
+			Title:                    title,
+			Description:              description,
+			Status:                   lifecycle.Status,
+			OpensAt:                  nullTime(lifecycle.OpensAt),
+			ClosesAt:                 nullTime(lifecycle.ClosesAt),
+			MaxResponses:             nullInt32(lifecycle.MaxResponses),
+			OwnerID:                  pgtype.UUID{Bytes: ownerID, Valid: true},
+			RespondentIdentity:       respondents.Identity,
+			OneResponsePerRespondent: respondents.OneResponse,
+			InviteOnly:               respondents.InviteOnly,
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, sectionRequest := range sectionRequest {
+			if utf8.RuneCountInString(sectionRequest.Title) > 255 {
+				return ErrSectionTitleTooLong
+			}
+			sections[i], err = s.sectionStore.Create(ctx, form.ID, sectionRequest.Title, sectionRequest.Description, int32(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		logic := make([]*question.Logic, len(questionRequest))
+		for i, questionRequest := range questionRequest {
+			sectionID := uuid.Nil
+			if sectionIndexes != nil {
+				sectionID = sections[sectionIndexes[i]].SectionID
+			}
+
+			q, err := s.questionStore.Create(ctx, form.ID, sectionID, questionRequest.QuestionText, questionRequest.Description, string(questionRequest.QuestionType), questionRequest.IsRequired, int32(i), questionRequest.Config, questionRequest.Validation, questionRequest.Options)
+			if err != nil {
+				return err
+			}
+			questions[i] = q
+			logic[i] = questionRequest.Logic
+		}
+
+		err = s.applyLogic(ctx, form.ID, questions, logic)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.versionStore.Publish(ctx, form.ID, questions)
+		return err
+	})
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	return toQuestionsForm(form, questions, sections), nil
+}
+
+// flattenSections lists the questions of all sections in form order together
+// with the index of the section each question belongs to.
+func flattenSections(sectionRequest []SectionRequest) ([]QuestionRequest, []int) {
+	var questionRequest []QuestionRequest
+	var sectionIndexes []int
+	for i, sectionRequest := range sectionRequest {
+		for _, q := range sectionRequest.Questions {
+			questionRequest = append(questionRequest, q)
+			sectionIndexes = append(sectionIndexes, i)
+		}
+	}
+	return questionRequest, sectionIndexes
+}
+
+// Duplicate copies the form with all of its questions and options into a new
+// form in one transaction. Questions and options get new IDs; submissions are
+// not copied. The copy starts out as a draft with the original's schedule and
+// response limit and belongs to ownerID. An empty title names the copy after
+// the original.
+func (s *Service) Duplicate(ctx context.Context, id uuid.UUID, ownerID uuid.UUID, title string) (QuestionsForm, error) {
+	var duplicate QuestionsForm
+	err := s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		original, err := s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if title == "" {
+			title = copyTitle(original.Title)
+		}
+
+		lifecycle := original.Lifecycle
+		lifecycle.Status = FormStatusDraft
+
+		copyQuestion := func(q question.OptionsQuestion) QuestionRequest {
+			optionTexts := make([]string, len(q.Options))
+			for j, option := range q.Options {
+				optionTexts[j] = option.OptionText
+			}
+			return QuestionRequest{
+				QuestionType: QuestionType(q.QuestionType),
+				IsRequired:   q.IsRequired,
+				QuestionText: q.QuestionText,
+				Description:  q.Description,
+				Config:       q.Config,
+				Validation:   q.Validation,
+				Logic:        question.IndexLogic(q.Logic, original.Questions, original.sectionIDs()),
+				Options:      optionTexts,
+			}
+		}
+
+		if len(original.Sections) > 0 {
+			sectionRequests := make([]SectionRequest, len(original.Sections))
+			for i, originalSection := range original.Sections {
+				sectionRequests[i] = SectionRequest{
+					Title:       originalSection.Title,
+					Description: originalSection.Description,
+				}
+				for _, q := range originalSection.Questions {
+					sectionRequests[i].Questions = append(sectionRequests[i].Questions, copyQuestion(q))
+				}
+			}
+
+			duplicate, err = s.Create(ctx, ownerID, title, original.Description, lifecycle, original.Respondents, nil, sectionRequests)
+			return err
+		}
+
+		questionRequests := make([]QuestionRequest, len(original.Questions))
+		for i, q := range original.Questions {
+			questionRequests[i] = copyQuestion(q)
+		}
+
+		duplicate, err = s.Create(ctx, ownerID, title, original.Description, lifecycle, original.Respondents, questionRequests, nil)
+		return err
+	})
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	return duplicate, nil
+}
+
+// copyTitle appends a copy marker to title, shortening it to fit the 255
+// character limit of forms.title.
+func copyTitle(title string) string {
+	const suffix = " (copy)"
+	runes := []rune(title)
+	if maxLen := 255 - len(suffix); len(runes) > maxLen {
+		runes = runes[:maxLen]
+	}
+	return string(runes) + suffix
+}
+
+// Update changes the form title, the description when it is not nil and,
+// when questionRequest or sectionRequest is not nil, reconciles the form's sections and questions with it in the same
+// transaction: sections and questions with an ID are updated, those without
+// one are created and those missing from the request are deleted. A flat
+// questionRequest removes every section of the form. The new question tree is
+// published as a new version, leaving earlier submissions attached to their
+// own version.
+func (s *Service) Update(ctx context.Context, id uuid.UUID, title string, description *string, questionRequest []UpdateQuestionRequest, sectionRequest []UpdateSectionRequest) (QuestionsForm, error) {
+	if questionRequest != nil && sectionRequest != nil {
+		return QuestionsForm{}, ErrQuestionsAndSections
+	}
+	if description != nil && utf8.RuneCountInString(*description) > maxDescriptionLength {
+		return QuestionsForm{}, ErrDescriptionTooLong
+	}
+
+	var nullDescription pgtype.Text
+	if description != nil {
+		nullDescription = pgtype.Text{String: *description, Valid: true}
+	}
+
+	err := s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		_, err := s.querier.Update(ctx, UpdateParams{
+			ID:          id,
+			Title:       title,
+			Description: nullDescription,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrFormNotFound
+			}
+			return err
+		}
+
+		if questionRequest == nil && sectionRequest == nil {
+			return nil
+		}
+
+		sectionIDs, err := s.updateSections(ctx, id, sectionRequest)
+		if err != nil {
+			return err
+		}
+
+		var questionSectionIDs []uuid.UUID
+		if sectionRequest != nil {
+			for i, sectionRequest := range sectionRequest {
+				for _, q := range sectionRequest.Questions {
+					questionRequest = append(questionRequest, q)
+					questionSectionIDs = append(questionSectionIDs, sectionIDs[i])
+				}
+			}
+		}
+
+		err = s.updateQuestions(ctx, id, questionRequest, questionSectionIDs)
+		if err != nil {
+			return err
+		}
+
+		return s.publish(ctx, id)
+	})
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// updateSections reconciles the form's sections with sectionRequest and
+// returns the ID of each requested section. Questions of deleted sections are
+// moved by updateQuestions afterwards.
+func (s *Service) updateSections(ctx context.Context, formID uuid.UUID, sectionRequest []UpdateSectionRequest) ([]uuid.UUID, error) {
+	existing, err := s.sectionStore.GetByFormID(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[uuid.UUID]bool, len(existing))
+	for _, sec := range existing {
+		kept[sec.SectionID] = false
+	}
+
+	sectionIDs := make([]uuid.UUID, len(sectionRequest))
+	for i, sectionRequest := range sectionRequest {
+		if utf8.RuneCountInString(sectionRequest.Title) > 255 {
+			return nil, ErrSectionTitleTooLong
+		}
+
+		var sec section.Response
+		if sectionRequest.SectionID == uuid.Nil {
+			sec, err = s.sectionStore.Create(ctx, formID, sectionRequest.Title, sectionRequest.Description, int32(i))
+		} else if isKept, ok := kept[sectionRequest.SectionID]; ok && !isKept {
+			kept[sectionRequest.SectionID] = true
+			sec, err = s.sectionStore.Update(ctx, formID, sectionRequest.SectionID, sectionRequest.Title, sectionRequest.Description, int32(i))
+		} else {
+			err = section.ErrSectionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		sectionIDs[i] = sec.SectionID
+	}
+
+	for sectionID, isKept := range kept {
+		if isKept {
+			continue
+		}
+		err := s.sectionStore.Delete(ctx, formID, sectionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sectionIDs, nil
+}
+
+// updateQuestions reconciles the form's questions with questionRequest,
+// placing each question in the section at the same index of sectionIDs, or in
+// no section when sectionIDs is nil.
+func (s *Service) updateQuestions(ctx context.Context, formID uuid.UUID, questionRequest []UpdateQuestionRequest, sectionIDs []uuid.UUID) error {
+	existing, err := s.questionStore.GetByFormID(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[uuid.UUID]bool, len(existing))
+	for _, q := range existing {
+		kept[q.QuestionID] = false
+	}
+
+	questions := make([]question.OptionsQuestion, len(questionRequest))
+	logic := make([]*question.Logic, len(questionRequest))
 	for i, questionRequest := range questionRequest {
-		q, err := s.questionStore.Create(ctx, form.ID, questionRequest.QuestionText, string(questionRequest.QuestionType), questionRequest.IsRequired, questionRequest.Options)
+		logic[i] = questionRequest.Logic
+
+		sectionID := uuid.Nil
+		if sectionIDs != nil {
+			sectionID = sectionIDs[i]
+		}
+
+		if questionRequest.QuestionID == uuid.Nil {
+			q, err := s.questionStore.Create(ctx, formID, sectionID, questionRequest.QuestionText, questionRequest.Description, string(questionRequest.QuestionType), questionRequest.IsRequired, int32(i), questionRequest.Config, questionRequest.Validation, optionTexts(questionRequest.Options))
+			if err != nil {
+				return err
+			}
+			questions[i] = q
+			continue
+		}
+
+		if isKept, ok := kept[questionRequest.QuestionID]; !ok || isKept {
+			return question.ErrQuestionNotFound
+		}
+		kept[questionRequest.QuestionID] = true
+
+		q, err := s.questionStore.Update(ctx, formID, sectionID, questionRequest.QuestionID, questionRequest.QuestionText, questionRequest.Description, string(questionRequest.QuestionType), questionRequest.IsRequired, int32(i), questionRequest.Config, questionRequest.Validation, questionRequest.Options)
+		if err != nil {
+			return err
+		}
 		questions[i] = q
+	}
+
+	for questionID, isKept := range kept {
+		if isKept {
+			continue
+		}
+		err := s.questionStore.Delete(ctx, formID, questionID)
 		if err != nil {
-			return QuestionsForm{}, err
+			return err
 		}
 	}
 
-	return QuestionsForm{
-		FormID:    form.ID,
-		Title:     form.Title,
-		Questions: questions,
-	}, nil
+	return s.applyLogic(ctx, formID, questions, logic)
+}
+
+// applyLogic resolves the requested logic of every question against the
+// final question and section lists and stores it, clearing logic that was
+// left out.
+func (s *Service) applyLogic(ctx context.Context, formID uuid.UUID, questions []question.OptionsQuestion, logic []*question.Logic) error {
+	sections, err := s.sectionStore.GetByFormID(ctx, formID)
+	if err != nil {
+		return err
+	}
+	sectionIDs := make([]uuid.UUID, len(sections))
+	for i, sec := range sections {
+		sectionIDs[i] = sec.SectionID
+	}
+
+	for i, q := range questions {
+		resolved, err := question.ResolveLogic(logic[i], questions, sectionIDs, i)
+		if err != nil {
+			return fmt.Errorf("questions[%d].logic: %w", i, err)
+		}
+		if resolved == nil && q.Logic == nil {
+			continue
+		}
+
+		err = s.questionStore.SetLogic(ctx, formID, q.QuestionID, resolved)
+		if err != nil {
+			return err
+		}
+		questions[i].Logic = resolved
+	}
+
+	return nil
+}
+
+// Reorder rewrites the positions of the form's questions, and optionally of
+// their options, in one transaction. Forms with sections are reordered by
+// section, which also moves questions between sections. The request must list
+// every section and question of the form exactly once, and the new order must
+// keep every condition pointing at an earlier question and every jump going
+// forward.
+func (s *Service) Reorder(ctx context.Context, id uuid.UUID, orderRequest []OrderQuestionRequest, sectionOrder []OrderSectionRequest) (QuestionsForm, error) {
+	err := s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(existing.Sections) != len(sectionOrder) || (len(sectionOrder) > 0 && len(orderRequest) > 0) {
+			return ErrInvalidSectionOrder
+		}
+		listedSections := make(map[uuid.UUID]bool, len(sectionOrder))
+		for _, sectionOrder := range sectionOrder {
+			listedSections[sectionOrder.SectionID] = true
+		}
+		for _, sec := range existing.Sections {
+			if !listedSections[sec.SectionID] {
+				return ErrInvalidSectionOrder
+			}
+		}
+
+		var sectionIDs []uuid.UUID
+		for i, sectionOrder := range sectionOrder {
+			err := s.sectionStore.UpdatePosition(ctx, id, sectionOrder.SectionID, int32(i))
+			if err != nil {
+				return err
+			}
+			for _, q := range sectionOrder.Questions {
+				orderRequest = append(orderRequest, q)
+				sectionIDs = append(sectionIDs, sectionOrder.SectionID)
+			}
+		}
+
+		if len(existing.Questions) != len(orderRequest) {
+			return ErrInvalidQuestionOrder
+		}
+		listed := make(map[uuid.UUID]bool, len(orderRequest))
+		for _, orderRequest := range orderRequest {
+			listed[orderRequest.QuestionID] = true
+		}
+		for _, q := range existing.Questions {
+			if !listed[q.QuestionID] {
+				return ErrInvalidQuestionOrder
+			}
+		}
+
+		for i, orderRequest := range orderRequest {
+			sectionID := uuid.Nil
+			if sectionIDs != nil {
+				sectionID = sectionIDs[i]
+			}
+
+			err := s.questionStore.Reorder(ctx, id, sectionID, orderRequest.QuestionID, int32(i), orderRequest.OptionIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		questions, err := s.questionStore.GetByFormID(ctx, id)
+		if err != nil {
+			return err
+		}
+		newSectionIDs := make([]uuid.UUID, len(sectionOrder))
+		for i, sectionOrder := range sectionOrder {
+			newSectionIDs[i] = sectionOrder.SectionID
+		}
+		for i, q := range questions {
+			_, err := question.ResolveLogic(q.Logic, questions, newSectionIDs, i)
+			if err != nil {
+				return fmt.Errorf("questions[%d].logic: %w", i, err)
+			}
+		}
+
+		_, err = s.versionStore.Publish(ctx, id, questions)
+		return err
+	})
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	return s.GetByID(ctx, id)
 }
 
-func (s *Service) Update(ctx context.Context, id uuid.UUID, title string) (QuestionsForm, error) {
-	form, err := s.querier.Update(ctx, UpdateParams{
-		ID:    id,
-		Title: title,
+// publish snapshots the current question tree of the form as a new version.
+func (s *Service) publish(ctx context.Context, formID uuid.UUID) error {
+	questions, err := s.questionStore.GetByFormID(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.versionStore.Publish(ctx, formID, questions)
+	return err
+}
+
+func optionTexts(optionsReq []options.Request) []string {
+	texts := make([]string, len(optionsReq))
+	for i, o := range optionsReq {
+		texts[i] = o.OptionText
+	}
+	return texts
+}
+
+// CheckPermission returns ErrPermissionDenied unless the user has at least the
+// required role on the form. The owner of the form has every role; everyone
+// else has the role they were granted as a collaborator. Forms without an
+// owner can be managed by nobody until one is assigned.
+func (s *Service) CheckPermission(ctx context.Context, id uuid.UUID, userID uuid.UUID, required collaborator.Role) error {
+	owner, err := s.querier.GetOwnerID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFormNotFound
+		}
+		return err
+	}
+
+	if owner.Valid && uuid.UUID(owner.Bytes) == userID {
+		return nil
+	}
+
+	role, err := s.roleStore.GetRole(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, collaborator.ErrCollaboratorNotFound) {
+			return ErrPermissionDenied
+		}
+		return err
+	}
+
+	if !role.Allows(required) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// SetLifecycle replaces the status, schedule and response limit of the form.
+// A lifecycle without a status opens the form.
+func (s *Service) SetLifecycle(ctx context.Context, id uuid.UUID, lifecycle Lifecycle) (QuestionsForm, error) {
+	lifecycle, err := lifecycle.normalize()
+	if err != nil {
+		return QuestionsForm{}, err
+	}
+
+	_, err = s.querier.UpdateLifecycle(ctx, UpdateLifecycleParams{
+		ID:           id,
+		Status:       lifecycle.Status,
+		OpensAt:      nullTime(lifecycle.OpensAt),
+		ClosesAt:     nullTime(lifecycle.ClosesAt),
+		MaxResponses: nullInt32(lifecycle.MaxResponses),
 	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return QuestionsForm{}, ErrFormNotFound
+		}
+		return QuestionsForm{}, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// CheckOpen returns an error wrapping ErrNotAcceptingResponses when the form
+// with the lifecycle does not take a new submission right now. Unlike
+// CheckAcceptingResponses it locks nothing, so it only tells whether the form
+// is worth showing to respondents.
+func (s *Service) CheckOpen(ctx context.Context, id uuid.UUID, lifecycle Lifecycle) error {
+	var responses int64
+	if lifecycle.MaxResponses != nil {
+		var err error
+		responses, err = s.querier.CountSubmissions(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return lifecycle.check(time.Now(), responses)
+}
+
+// SetRespondents replaces what the form records about its respondents.
+func (s *Service) SetRespondents(ctx context.Context, id uuid.UUID, respondents Respondents) (QuestionsForm, error) {
+	respondents, err := respondents.normalize()
 	if err != nil {
 		return QuestionsForm{}, err
 	}
 
-	questions, err := s.questionStore.GetByFormID(ctx, form.ID)
+	_, err = s.querier.UpdateRespondents(ctx, UpdateRespondentsParams{
+		RespondentIdentity:       respondents.Identity,
+		OneResponsePerRespondent: respondents.OneResponse,
+		InviteOnly:               respondents.InviteOnly,
+		ID:                       id,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return QuestionsForm{}, ErrFormNotFound
+		}
 		return QuestionsForm{}, err
 	}
 
-	return QuestionsForm{
-		FormID:    form.ID,
-		Title:     form.Title,
-		Questions: questions,
-	}, nil
+	return s.GetByID(ctx, id)
+}
+
+// CheckAcceptingResponses returns an error wrapping ErrNotAcceptingResponses
+// when the form does not take a new submission right now, and otherwise what
+// the form records about the respondent. Invite-only forms use up the
+// invitation of the respondent. It is meant to run in the transaction that
+// stores the submission: the form row stays locked until then, so concurrent
+// submissions cannot overshoot the response limit, and the invitation is
+// only used up if the submission is stored.
+func (s *Service) CheckAcceptingResponses(ctx context.Context, id uuid.UUID, respondent submission.Respondent) (submission.Respondent, error) {
+	form, err := s.querier.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return submission.Respondent{}, ErrFormNotFound
+		}
+		return submission.Respondent{}, err
+	}
+
+	var responses int64
+	if form.MaxResponses.Valid {
+		responses, err = s.querier.CountSubmissions(ctx, id)
+		if err != nil {
+			return submission.Respondent{}, err
+		}
+	}
+
+	err = toLifecycle(form).check(time.Now(), responses)
+	if err != nil {
+		return submission.Respondent{}, err
+	}
+
+	recorded, err := toRespondents(form).check(respondent)
+	if err != nil {
+		return submission.Respondent{}, err
+	}
+
+	if form.InviteOnly {
+		invitationID, err := s.invitationConsumer.Consume(ctx, id, respondent.Invitation)
+		if err != nil {
+			if errors.Is(err, invitation.ErrInvalidInvitation) {
+				return submission.Respondent{}, ErrInvitationRequired
+			}
+			return submission.Respondent{}, err
+		}
+		recorded.InvitationID = &invitationID
+	}
+
+	return recorded, nil
 }
 
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
@@ -0,0 +1,155 @@
+package form
+
+import (
+	"context"
+	"database-final-project/internal/database"
+	"database-final-project/internal/options"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// failingOptionQuerier fails the option insert with index failAt, counting
+// from 0, and runs every other query on Postgres.
+type failingOptionQuerier struct {
+	options.Querier
+	failAt  int
+	inserts int
+}
+
+var errInsertFailed = errors.New("insert failed")
+
+func (q *failingOptionQuerier) Create(ctx context.Context, arg options.CreateParams) (options.Option, error) {
+	defer func() { q.inserts++ }()
+	if q.inserts == q.failAt {
+		return options.Option{}, errInsertFailed
+	}
+	return q.Querier.Create(ctx, arg)
+}
+
+func TestCreateRollsBackOnFailedOptionInsert(t *testing.T) {
+	db := newTestDB(t, nil)
+	owner := newTestUser(t, db)
+
+	questions := []QuestionRequest{
+		{QuestionType: QuestionTypeSelect, QuestionText: "Color", Options: []string{"Red", "Green"}},
+		{QuestionType: QuestionTypeMultiselect, QuestionText: "Fruit", Options: []string{"Apple", "Pear"}},
+	}
+
+	tests := []struct {
+		name    string
+		failAt  int
+		wantErr error
+		want    map[string]int
+	}{
+		{name: "first option fails", failAt: 0, wantErr: errInsertFailed, want: map[string]int{}},
+		{name: "option of second question fails", failAt: 3, wantErr: errInsertFailed, want: map[string]int{}},
+		{name: "no option fails", failAt: -1, want: map[string]int{"forms": 1, "questions": 2, "options": 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := newTestService(db, &failingOptionQuerier{Querier: options.New(db), failAt: tt.failAt})
+
+			title := "Rollback " + uuid.NewString()
+			_, err := service.Create(ctx, owner, title, "", Lifecycle{}, Respondents{}, questions, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+
+			rows := countFormRows(t, db, title)
+			for _, table := range []string{"forms", "questions", "options"} {
+				if rows[table] != tt.want[table] {
+					t.Errorf("%s has %d rows, want %d", table, rows[table], tt.want[table])
+				}
+			}
+		})
+	}
+}
+
+// countFormRows counts the forms titled title and their questions and options.
+func countFormRows(t *testing.T, db *database.DB, title string) map[string]int {
+	t.Helper()
+
+	var forms, questions, options int
+	err := db.QueryRow(context.Background(), `
+		SELECT (SELECT count(*) FROM forms f WHERE f.title = $1),
+		       (SELECT count(*) FROM questions q JOIN forms f ON f.id = q.form_id WHERE f.title = $1),
+		       (SELECT count(*) FROM options o JOIN questions q ON q.id = o.question_id JOIN forms f ON f.id = q.form_id WHERE f.title = $1)`,
+		title,
+	).Scan(&forms, &questions, &options)
+	if err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	return map[string]int{"forms": forms, "questions": questions, "options": options}
+}
+
+// createListForms gives owner the number of forms asked for, each with a
+// section of questions with options of their own.
+func createListForms(tb testing.TB, service *Service, owner uuid.UUID, forms int) {
+	tb.Helper()
+
+	questions := make([]QuestionRequest, 5)
+	for i := range questions {
+		questions[i] = QuestionRequest{QuestionType: QuestionTypeSelect, QuestionText: fmt.Sprintf("Question %d", i), Options: []string{"Red", "Green", "Blue"}}
+	}
+	sections := []SectionRequest{{Title: "Page", Questions: questions}}
+
+	for i := range forms {
+		_, err := service.Create(context.Background(), owner, fmt.Sprintf("Survey %d", i), "", Lifecycle{}, Respondents{}, nil, sections)
+		if err != nil {
+			tb.Fatalf("create form: %v", err)
+		}
+	}
+}
+
+func TestListQueryCountIsConstant(t *testing.T) {
+	counter := &queryCounter{}
+	db := newTestDB(t, counter)
+	service := newTestService(db, options.New(db))
+
+	// Forms, count, sections, questions and options of the whole page.
+	const want = 5
+
+	for _, forms := range []int{1, 10, 100} {
+		owner := newTestUser(t, db)
+		createListForms(t, service, owner, forms)
+
+		counter.queries.Store(0)
+		resp, err := service.List(context.Background(), ListParams{UserID: owner, Limit: 100, Sort: SortCreatedAt})
+		if err != nil {
+			t.Fatalf("List() of %d forms error = %v", forms, err)
+		}
+		if len(resp.Forms) != forms {
+			t.Fatalf("List() returned %d forms, want %d", len(resp.Forms), forms)
+		}
+		if got := counter.queries.Load(); got != want {
+			t.Errorf("List() of %d forms ran %d queries, want %d", forms, got, want)
+		}
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	counter := &queryCounter{}
+	db := newTestDB(b, counter)
+	service := newTestService(db, options.New(db))
+
+	for _, forms := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("%d forms", forms), func(b *testing.B) {
+			owner := newTestUser(b, db)
+			createListForms(b, service, owner, forms)
+
+			counter.queries.Store(0)
+			for b.Loop() {
+				_, err := service.List(context.Background(), ListParams{UserID: owner, Limit: 100, Sort: SortCreatedAt})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(counter.queries.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
@@ -2,12 +2,115 @@ package form
 
 import (
 	"database-final-project/internal/question"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortTitle     = "title"
+)
+
+// SummaryForm is a form without its questions, as listed in summary mode.
+// The Markdown description is only rendered into DescriptionHTML on request.
+type SummaryForm struct {
+	FormID          uuid.UUID  `json:"form_id"`
+	OwnerID         *uuid.UUID `json:"owner_id,omitempty"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	DescriptionHTML string     `json:"description_html,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Lifecycle
+	Respondents
+}
+
+// QuestionsForm is a form with its questions in order, an empty list for a
+// form without questions. When the form is split into sections, Sections
+// groups the same questions by section.
 type QuestionsForm struct {
-	FormID    uuid.UUID                  `json:"form_id"`
-	Title     string                     `json:"title"`
+	SummaryForm
 	Questions []question.OptionsQuestion `json:"questions"`
+	Sections  []QuestionsSection         `json:"sections,omitempty"`
+}
+
+// Lifecycle decides when a form accepts submissions: only while it is open,
+// inside the optional OpensAt and ClosesAt window and below the optional
+// MaxResponses limit.
+type Lifecycle struct {
+	Status       FormStatus `json:"status"`
+	OpensAt      *time.Time `json:"opens_at,omitempty"`
+	ClosesAt     *time.Time `json:"closes_at,omitempty"`
+	MaxResponses *int32     `json:"max_responses,omitempty"`
+}
+
+// Respondents decides who fills in a form and what it records about them:
+// nothing for anonymous forms, the signed-in user and an optional email
+// otherwise. With Identity required one of the two must be given, and
+// OneResponse then limits every signed-in user to a single submission and
+// turns away everyone else. Invite-only forms are only shown to and filled in
+// by holders of an invitation.
+type Respondents struct {
+	Identity    RespondentIdentity `json:"respondent_identity"`
+	OneResponse bool               `json:"one_response_per_respondent"`
+	InviteOnly  bool               `json:"invite_only"`
+}
+
+// QuestionsSection is a page of a form with the questions shown on it.
+type QuestionsSection struct {
+	SectionID       uuid.UUID                  `json:"section_id"`
+	Title           string                     `json:"title"`
+	Description     string                     `json:"description"`
+	DescriptionHTML string                     `json:"description_html,omitempty"`
+	Position        int32                      `json:"position"`
+	Questions       []question.OptionsQuestion `json:"questions"`
+}
+
+// sectionIDs lists the IDs of the form's sections in order.
+func (f QuestionsForm) sectionIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(f.Sections))
+	for i, s := range f.Sections {
+		ids[i] = s.SectionID
+	}
+	return ids
+}
+
+// ListParams selects one page of the forms UserID owns or collaborates on. Cursor is the
+// NextCursor of the previous page, or empty for the first page.
+type ListParams struct {
+	UserID     uuid.UUID
+	Limit      int32
+	Cursor     string
+	Sort       string
+	Descending bool
+	Search     string
+	Summary    bool
+}
+
+type ListResponse struct {
+	Forms      []QuestionsForm `json:"forms"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Total      int64           `json:"total"`
+}
+
+// SummaryListResponse is a ListResponse in summary mode.
+type SummaryListResponse struct {
+	Forms      []SummaryForm `json:"forms"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Total      int64         `json:"total"`
+}
+
+func (r ListResponse) summary() SummaryListResponse {
+	forms := make([]SummaryForm, len(r.Forms))
+	for i, form := range r.Forms {
+		forms[i] = form.SummaryForm
+	}
+
+	return SummaryListResponse{
+		Forms:      forms,
+		NextCursor: r.NextCursor,
+		Total:      r.Total,
+	}
 }
@@ -0,0 +1,11 @@
+package invitation
+
+import "errors"
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvalidInvitation  = errors.New("invitation is invalid, used up or expired")
+	ErrInvalidCount       = errors.New("count must be between 1 and 1000")
+	ErrInvalidMaxUses     = errors.New("max_uses must be at least 1")
+	ErrInvalidExpiry      = errors.New("expires_at must be in the future")
+)
@@ -0,0 +1,27 @@
+-- name: GetByFormID :many
+SELECT * FROM form_invitations WHERE form_id = $1 ORDER BY created_at, id;
+
+-- name: CreateBatch :many
+INSERT INTO form_invitations (form_id, token_hash, max_uses, expires_at)
+SELECT @form_id, unnest(@token_hashes::bytea[]), @max_uses, sqlc.narg(expires_at)
+RETURNING *;
+
+-- name: GetUsable :one
+SELECT *
+FROM form_invitations
+WHERE form_id = $1
+  AND token_hash = $2
+  AND uses < max_uses
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP);
+
+-- name: Consume :one
+UPDATE form_invitations
+SET uses = uses + 1
+WHERE form_id = $1
+  AND token_hash = $2
+  AND uses < max_uses
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+RETURNING id;
+
+-- name: Delete :execrows
+DELETE FROM form_invitations WHERE id = $1 AND form_id = $2;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS form_invitations
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    token_hash BYTEA       NOT NULL UNIQUE,
+    max_uses   INTEGER     NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    uses       INTEGER     NOT NULL DEFAULT 0 CHECK (uses <= max_uses),
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS form_invitations_form_id_created_at_idx ON form_invitations (form_id, created_at);
@@ -0,0 +1,174 @@
+package invitation
+
+import (
+	"context"
+	"database-final-project/internal"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// maxBatchSize is the most invitations generated by one request.
+const maxBatchSize = 1000
+
+type Querier interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]FormInvitation, error)
+	CreateBatch(ctx context.Context, arg CreateBatchParams) ([]FormInvitation, error)
+	GetUsable(ctx context.Context, arg GetUsableParams) (FormInvitation, error)
+	Consume(ctx context.Context, arg ConsumeParams) (uuid.UUID, error)
+	Delete(ctx context.Context, arg DeleteParams) (int64, error)
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (s *Service) GetByFormID(ctx context.Context, formID uuid.UUID) ([]Response, error) {
+	invitations, err := s.queries.GetByFormID(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]Response, 0, len(invitations))
+	for _, invitation := range invitations {
+		responses = append(responses, toResponse(invitation))
+	}
+
+	return responses, nil
+}
+
+// Create generates count invitations to the form in one statement. The tokens
+// are only returned here; just their hashes are stored.
+func (s *Service) Create(ctx context.Context, formID uuid.UUID, count int32, maxUses int32, expiresAt *time.Time) ([]CreatedResponse, error) {
+	if count < 1 || count > maxBatchSize {
+		return nil, ErrInvalidCount
+	}
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if maxUses < 1 {
+		return nil, ErrInvalidMaxUses
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	tokens := make([]string, count)
+	tokenHashes := make([][]byte, count)
+	for i := range tokens {
+		token, err := internal.NewToken()
+		if err != nil {
+			return nil, err
+		}
+		tokens[i] = token
+		tokenHashes[i] = internal.HashToken(token)
+	}
+
+	params := CreateBatchParams{
+		FormID:      formID,
+		TokenHashes: tokenHashes,
+		MaxUses:     maxUses,
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	invitations, err := s.queries.CreateBatch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING gives the rows in no guaranteed order, so match them to their
+	// tokens by hash.
+	tokensByHash := make(map[string]string, len(tokens))
+	for i, token := range tokens {
+		tokensByHash[string(tokenHashes[i])] = token
+	}
+
+	created := make([]CreatedResponse, len(invitations))
+	for i, invitation := range invitations {
+		created[i] = CreatedResponse{
+			Response: toResponse(invitation),
+			Token:    tokensByHash[string(invitation.TokenHash)],
+		}
+	}
+
+	return created, nil
+}
+
+// Check returns ErrInvalidInvitation unless the token is a usable invitation
+// to the form. It does not use the invitation up.
+func (s *Service) Check(ctx context.Context, formID uuid.UUID, token string) error {
+	_, err := s.queries.GetUsable(ctx, GetUsableParams{
+		FormID:    formID,
+		TokenHash: internal.HashToken(token),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidInvitation
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Consume uses the invitation with the token up once and returns its ID, or
+// ErrInvalidInvitation. It is meant to run in the transaction that stores the
+// submission made with the invitation.
+func (s *Service) Consume(ctx context.Context, formID uuid.UUID, token string) (uuid.UUID, error) {
+	id, err := s.queries.Consume(ctx, ConsumeParams{
+		FormID:    formID,
+		TokenHash: internal.HashToken(token),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInvalidInvitation
+		}
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	deleted, err := s.queries.Delete(ctx, DeleteParams{
+		ID:     id,
+		FormID: formID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func toResponse(invitation FormInvitation) Response {
+	var expiresAt *time.Time
+	if invitation.ExpiresAt.Valid {
+		expiresAt = &invitation.ExpiresAt.Time
+	}
+
+	return Response{
+		InvitationID: invitation.ID,
+		MaxUses:      invitation.MaxUses,
+		Uses:         invitation.Uses,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    invitation.CreatedAt.Time,
+	}
+}
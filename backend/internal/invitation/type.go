@@ -0,0 +1,30 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateRequest generates Count invitations, each usable MaxUses times until
+// the optional ExpiresAt. MaxUses defaults to 1.
+type CreateRequest struct {
+	Count     int32      `json:"count" validate:"required"`
+	MaxUses   int32      `json:"max_uses,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Response struct {
+	InvitationID uuid.UUID  `json:"invitation_id"`
+	MaxUses      int32      `json:"max_uses"`
+	Uses         int32      `json:"uses"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreatedResponse is returned once when an invitation is generated. Only a
+// hash of Token is stored, so it cannot be shown again.
+type CreatedResponse struct {
+	Response
+	Token string `json:"token"`
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// htmlPolicy is safe for concurrent use once configured.
+	htmlPolicy = bluemonday.UGCPolicy().AddTargetBlankToFullyQualifiedLinks(true)
+)
+
+// RenderMarkdown converts Markdown written by form authors into HTML that can
+// be inserted into a page as is. Raw HTML in the source is not rendered, and
+// the output is sanitized against an allow-list of tags and attributes as a
+// second line of defence.
+func RenderMarkdown(source string) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(source), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,7 @@
+package options
+
+import "errors"
+
+var (
+	ErrOptionNotFound = errors.New("option not found")
+)
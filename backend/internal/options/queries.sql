@@ -2,9 +2,37 @@
 SELECT *
 FROM options
 WHERE question_id = $1
-ORDER BY created_at ASC;
+ORDER BY position ASC, created_at ASC;
 
 -- name: Create :one
-INSERT INTO options (question_id, text)
-VALUES ($1, $2)
-RETURNING *;
\ No newline at end of file
+INSERT INTO options (question_id, text, position)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: Update :one
+UPDATE options
+SET text       = $3,
+    position   = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND question_id = $2
+RETURNING *;
+
+-- name: UpdatePosition :exec
+UPDATE options
+SET position   = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND question_id = $2;
+
+-- name: Delete :exec
+DELETE
+FROM options
+WHERE id = $1
+  AND question_id = $2;
+
+-- name: GetByQuestionIDs :many
+SELECT *
+FROM options
+WHERE question_id = ANY (@question_ids::uuid[])
+ORDER BY position ASC, created_at ASC;
@@ -4,5 +4,6 @@ CREATE TABLE IF NOT EXISTS options
     question_id UUID NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
     text TEXT NOT NULL,
     created_at  TIMESTAMPTZ NOT NULL     DEFAULT CURRENT_TIMESTAMP,
-    updated_at  TIMESTAMPTZ NOT NULL     DEFAULT CURRENT_TIMESTAMP
+    updated_at  TIMESTAMPTZ NOT NULL     DEFAULT CURRENT_TIMESTAMP,
+    position    INTEGER     NOT NULL     DEFAULT 0
 );
\ No newline at end of file
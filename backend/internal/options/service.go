@@ -2,14 +2,20 @@ package options
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 type Querier interface {
 	Create(ctx context.Context, arg CreateParams) (Option, error)
 	GetByQuestionID(ctx context.Context, questionID uuid.UUID) ([]Option, error)
+	GetByQuestionIDs(ctx context.Context, questionIds []uuid.UUID) ([]Option, error)
+	Update(ctx context.Context, arg UpdateParams) (Option, error)
+	UpdatePosition(ctx context.Context, arg UpdatePositionParams) error
+	Delete(ctx context.Context, arg DeleteParams) error
 }
 
 type Service struct {
@@ -32,26 +38,77 @@ func (s *Service) GetByQuestionID(ctx context.Context, questionID uuid.UUID) ([]
 
 	var responses []Response
 	for _, option := range options {
-		responses = append(responses, Response{
-			OptionID:   option.ID,
-			OptionText: option.Text,
-		})
+		responses = append(responses, toResponse(option))
 	}
 
 	return responses, nil
 }
 
-func (s *Service) Create(ctx context.Context, questionID uuid.UUID, text string) (Response, error) {
+// GetByQuestionIDs loads the options of several questions in one query,
+// grouped by question ID.
+func (s *Service) GetByQuestionIDs(ctx context.Context, questionIDs []uuid.UUID) (map[uuid.UUID][]Response, error) {
+	options, err := s.queries.GetByQuestionIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[uuid.UUID][]Response, len(questionIDs))
+	for _, option := range options {
+		responses[option.QuestionID] = append(responses[option.QuestionID], toResponse(option))
+	}
+
+	return responses, nil
+}
+
+func (s *Service) Create(ctx context.Context, questionID uuid.UUID, text string, position int32) (Response, error) {
 	option, err := s.queries.Create(ctx, CreateParams{
 		QuestionID: questionID,
 		Text:       text,
+		Position:   position,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return toResponse(option), nil
+}
+
+func (s *Service) Update(ctx context.Context, questionID uuid.UUID, id uuid.UUID, text string, position int32) (Response, error) {
+	option, err := s.queries.Update(ctx, UpdateParams{
+		ID:         id,
+		QuestionID: questionID,
+		Text:       text,
+		Position:   position,
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Response{}, ErrOptionNotFound
+		}
 		return Response{}, err
 	}
 
+	return toResponse(option), nil
+}
+
+func (s *Service) UpdatePosition(ctx context.Context, questionID uuid.UUID, id uuid.UUID, position int32) error {
+	return s.queries.UpdatePosition(ctx, UpdatePositionParams{
+		ID:         id,
+		QuestionID: questionID,
+		Position:   position,
+	})
+}
+
+func (s *Service) Delete(ctx context.Context, questionID uuid.UUID, id uuid.UUID) error {
+	return s.queries.Delete(ctx, DeleteParams{
+		ID:         id,
+		QuestionID: questionID,
+	})
+}
+
+func toResponse(option Option) Response {
 	return Response{
 		OptionID:   option.ID,
 		OptionText: option.Text,
-	}, nil
+		Position:   option.Position,
+	}
 }
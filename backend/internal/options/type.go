@@ -2,7 +2,13 @@ package options
 
 import "github.com/google/uuid"
 
+type Request struct {
+	OptionID   uuid.UUID `json:"option_id"`
+	OptionText string    `json:"option_text"`
+}
+
 type Response struct {
 	OptionID   uuid.UUID `json:"option_id"`
 	OptionText string    `json:"option_text"`
+	Position   int32     `json:"position"`
 }
@@ -0,0 +1,148 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+const (
+	defaultScaleMin = 1
+	defaultScaleMax = 5
+	maxScaleMax     = 10
+)
+
+// Config holds the settings of the question types that take any: the range
+// and step of number questions, the number of stars of rating questions and
+// the range and end labels of linear_scale questions.
+type Config struct {
+	Min      *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	Step     *float64 `json:"step,omitempty" yaml:"step,omitempty"`
+	MinLabel string   `json:"min_label,omitempty" yaml:"min_label,omitempty"`
+	MaxLabel string   `json:"max_label,omitempty" yaml:"max_label,omitempty"`
+}
+
+func (c *Config) isEmpty() bool {
+	return c == nil || *c == Config{}
+}
+
+// NormalizeConfig checks config against questionType and fills in defaults.
+// It returns nil for question types without settings and an error wrapping
+// ErrInvalidConfig when a setting does not fit the type.
+func NormalizeConfig(questionType string, config *Config) (*Config, error) {
+	switch QuestionType(questionType) {
+	case QuestionTypeNumber:
+		if config.isEmpty() {
+			return nil, nil
+		}
+		if config.MinLabel != "" || config.MaxLabel != "" {
+			return nil, fmt.Errorf("%w: number questions do not take labels", ErrInvalidConfig)
+		}
+		if config.Min != nil && config.Max != nil && *config.Min > *config.Max {
+			return nil, fmt.Errorf("%w: min must not be greater than max", ErrInvalidConfig)
+		}
+		if config.Step != nil && *config.Step <= 0 {
+			return nil, fmt.Errorf("%w: step must be greater than 0", ErrInvalidConfig)
+		}
+		normalized := *config
+		return &normalized, nil
+	case QuestionTypeRating:
+		normalized := Config{Max: float64Ptr(defaultScaleMax)}
+		if config != nil {
+			if config.Min != nil || config.Step != nil || config.MinLabel != "" || config.MaxLabel != "" {
+				return nil, fmt.Errorf("%w: rating questions only take max", ErrInvalidConfig)
+			}
+			if config.Max != nil {
+				normalized.Max = config.Max
+			}
+		}
+		if !isWhole(*normalized.Max) || *normalized.Max < 2 || *normalized.Max > maxScaleMax {
+			return nil, fmt.Errorf("%w: max must be a whole number between 2 and %d", ErrInvalidConfig, maxScaleMax)
+		}
+		return &normalized, nil
+	case QuestionTypeLinearScale:
+		normalized := Config{Min: float64Ptr(defaultScaleMin), Max: float64Ptr(defaultScaleMax)}
+		if config != nil {
+			if config.Step != nil {
+				return nil, fmt.Errorf("%w: linear_scale questions do not take step", ErrInvalidConfig)
+			}
+			if config.Min != nil {
+				normalized.Min = config.Min
+			}
+			if config.Max != nil {
+				normalized.Max = config.Max
+			}
+			normalized.MinLabel = config.MinLabel
+			normalized.MaxLabel = config.MaxLabel
+		}
+		if *normalized.Min != 0 && *normalized.Min != 1 {
+			return nil, fmt.Errorf("%w: min must be 0 or 1", ErrInvalidConfig)
+		}
+		if !isWhole(*normalized.Max) || *normalized.Max < 2 || *normalized.Max > maxScaleMax {
+			return nil, fmt.Errorf("%w: max must be a whole number between 2 and %d", ErrInvalidConfig, maxScaleMax)
+		}
+		return &normalized, nil
+	case QuestionTypeShortAnswer, QuestionTypeParagraph, QuestionTypeSelect, QuestionTypeMultiselect,
+		QuestionTypeEmail, QuestionTypeUrl, QuestionTypeDate, QuestionTypeTime:
+		if !config.isEmpty() {
+			return nil, fmt.Errorf("%w: %s questions do not take a configuration", ErrInvalidConfig, questionType)
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidQuestionType, questionType)
+	}
+}
+
+// HasOptions reports whether questions of the type are answered by choosing options.
+func HasOptions(questionType string) bool {
+	return QuestionType(questionType) == QuestionTypeSelect || QuestionType(questionType) == QuestionTypeMultiselect
+}
+
+// ScaleBounds returns the lowest and highest accepted answer of a rating or
+// linear_scale question, falling back to the defaults for snapshots taken
+// before the question had a configuration.
+func (q OptionsQuestion) ScaleBounds() (int, int) {
+	low, high := defaultScaleMin, defaultScaleMax
+	if q.Config == nil {
+		return low, high
+	}
+	if q.QuestionType == QuestionTypeLinearScale && q.Config.Min != nil {
+		low = int(*q.Config.Min)
+	}
+	if q.Config.Max != nil {
+		high = int(*q.Config.Max)
+	}
+	return low, high
+}
+
+func encodeConfig(config *Config) ([]byte, error) {
+	if config == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(config)
+}
+
+func decodeConfig(data []byte) (*Config, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var config Config
+	err := json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, err
+	}
+	if config.isEmpty() {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+func isWhole(f float64) bool {
+	return f == math.Trunc(f)
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
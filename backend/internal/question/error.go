@@ -0,0 +1,13 @@
+package question
+
+import "errors"
+
+var (
+	ErrQuestionNotFound    = errors.New("question not found")
+	ErrInvalidOptionOrder  = errors.New("order must list every option of the question exactly once")
+	ErrInvalidQuestionType = errors.New("unknown question type")
+	ErrInvalidConfig       = errors.New("invalid question configuration")
+	ErrInvalidValidation   = errors.New("invalid validation rules")
+	ErrInvalidLogic        = errors.New("invalid question logic")
+	ErrDescriptionTooLong  = errors.New("question description must be at most 5000 characters")
+)
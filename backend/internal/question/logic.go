@@ -0,0 +1,346 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Operators a Condition can test an earlier answer with.
+const (
+	OperatorSelected    = "selected"
+	OperatorNotSelected = "not_selected"
+	OperatorAnswered    = "answered"
+	OperatorNotAnswered = "not_answered"
+	OperatorEquals      = "equals"
+)
+
+// Ways the ShowIf conditions of a Logic are combined.
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// Logic decides whether a question is shown based on earlier answers. A
+// question with ShowIf conditions is only shown when all (or, with Match set
+// to "any", any) of them hold. The first Jump whose condition holds once the
+// question is answered skips the respondent ahead to a later question, to the
+// first question of a later section or to the end of the form. Hidden and
+// skipped questions are neither required nor stored.
+type Logic struct {
+	ShowIf []Condition `json:"show_if,omitempty" yaml:"show_if,omitempty"`
+	Match  string      `json:"match,omitempty" yaml:"match,omitempty"`
+	Jumps  []Jump      `json:"jumps,omitempty" yaml:"jumps,omitempty"`
+}
+
+// Condition tests the answer to a question. Requests may point at the
+// question and option by ID or by their zero-based index in the request; the
+// stored logic always uses IDs.
+type Condition struct {
+	QuestionID    *uuid.UUID `json:"question_id,omitempty" yaml:"question_id,omitempty"`
+	QuestionIndex *int       `json:"question_index,omitempty" yaml:"question_index,omitempty"`
+	Operator      string     `json:"operator" yaml:"operator"`
+	OptionID      *uuid.UUID `json:"option_id,omitempty" yaml:"option_id,omitempty"`
+	OptionIndex   *int       `json:"option_index,omitempty" yaml:"option_index,omitempty"`
+	// Value is compared with the answer of an equals condition, ignoring case
+	// and surrounding whitespace.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Jump skips to the question given by ToQuestionID or ToQuestionIndex, to the
+// section given by ToSectionID or ToSectionIndex, or to the end of the form
+// when End is set, if its condition holds.
+type Jump struct {
+	If              Condition  `json:"if" yaml:"if"`
+	ToQuestionID    *uuid.UUID `json:"to_question_id,omitempty" yaml:"to_question_id,omitempty"`
+	ToQuestionIndex *int       `json:"to_question_index,omitempty" yaml:"to_question_index,omitempty"`
+	ToSectionID     *uuid.UUID `json:"to_section_id,omitempty" yaml:"to_section_id,omitempty"`
+	ToSectionIndex  *int       `json:"to_section_index,omitempty" yaml:"to_section_index,omitempty"`
+	End             bool       `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+func (l *Logic) isEmpty() bool {
+	return l == nil || (len(l.ShowIf) == 0 && len(l.Jumps) == 0)
+}
+
+// MatchesAny reports whether a single ShowIf condition is enough to show the
+// question.
+func (l *Logic) MatchesAny() bool {
+	return l != nil && l.Match == MatchAny
+}
+
+// ResolveLogic checks the logic of the question at index against the ordered
+// questions and sections of its form and returns it with every reference
+// turned into an ID. Conditions may only refer to earlier questions, or for a
+// jump to the question itself, and jumps may only go forward, so the logic
+// can never loop. A jump to a section goes to its first question, so the
+// section must have one. It returns nil when there is no logic and an error
+// wrapping ErrInvalidLogic when a reference does not fit.
+func ResolveLogic(logic *Logic, questions []OptionsQuestion, sections []uuid.UUID, index int) (*Logic, error) {
+	if logic.isEmpty() {
+		return nil, nil
+	}
+
+	resolved := Logic{}
+	switch logic.Match {
+	case "", MatchAll:
+	case MatchAny:
+		resolved.Match = MatchAny
+	default:
+		return nil, fmt.Errorf("%w: match must be all or any", ErrInvalidLogic)
+	}
+
+	for i, condition := range logic.ShowIf {
+		c, err := resolveCondition(condition, questions, index, false)
+		if err != nil {
+			return nil, fmt.Errorf("show_if[%d]: %w", i, err)
+		}
+		resolved.ShowIf = append(resolved.ShowIf, c)
+	}
+
+	for i, jump := range logic.Jumps {
+		c, err := resolveCondition(jump.If, questions, index, true)
+		if err != nil {
+			return nil, fmt.Errorf("jumps[%d]: %w", i, err)
+		}
+
+		r := Jump{If: c, End: jump.End}
+		hasQuestion := jump.ToQuestionID != nil || jump.ToQuestionIndex != nil
+		hasSection := jump.ToSectionID != nil || jump.ToSectionIndex != nil
+		switch {
+		case jump.End:
+			if hasQuestion || hasSection {
+				return nil, fmt.Errorf("jumps[%d]: %w: a jump to the end has no target question or section", i, ErrInvalidLogic)
+			}
+		case hasQuestion && hasSection:
+			return nil, fmt.Errorf("jumps[%d]: %w: give either a target question or a target section", i, ErrInvalidLogic)
+		case hasSection:
+			target, err := findSection(sections, jump.ToSectionID, jump.ToSectionIndex)
+			if err != nil {
+				return nil, fmt.Errorf("jumps[%d]: %w", i, err)
+			}
+			first := FirstInSection(questions, sections[target])
+			if first < 0 {
+				return nil, fmt.Errorf("jumps[%d]: %w: section %d has no questions to jump to", i, ErrInvalidLogic, target)
+			}
+			if first <= index {
+				return nil, fmt.Errorf("jumps[%d]: %w: jumps can only go to a later section", i, ErrInvalidLogic)
+			}
+			r.ToSectionID = &sections[target]
+		default:
+			target, err := findQuestion(questions, jump.ToQuestionID, jump.ToQuestionIndex)
+			if err != nil {
+				return nil, fmt.Errorf("jumps[%d]: %w", i, err)
+			}
+			if target <= index {
+				return nil, fmt.Errorf("jumps[%d]: %w: jumps can only go to a later question", i, ErrInvalidLogic)
+			}
+			r.ToQuestionID = &questions[target].QuestionID
+		}
+		resolved.Jumps = append(resolved.Jumps, r)
+	}
+
+	return &resolved, nil
+}
+
+func resolveCondition(condition Condition, questions []OptionsQuestion, index int, allowSelf bool) (Condition, error) {
+	position, err := findQuestion(questions, condition.QuestionID, condition.QuestionIndex)
+	if err != nil {
+		return Condition{}, err
+	}
+	if position > index || (position == index && !allowSelf) {
+		return Condition{}, fmt.Errorf("%w: conditions can only refer to earlier questions", ErrInvalidLogic)
+	}
+
+	q := questions[position]
+	resolved := Condition{QuestionID: &q.QuestionID, Operator: condition.Operator}
+	hasOption := condition.OptionID != nil || condition.OptionIndex != nil
+
+	switch condition.Operator {
+	case OperatorSelected, OperatorNotSelected:
+		if !HasOptions(string(q.QuestionType)) {
+			return Condition{}, fmt.Errorf("%w: %s needs a select or multiselect question", ErrInvalidLogic, condition.Operator)
+		}
+		option, err := findOption(q, condition.OptionID, condition.OptionIndex)
+		if err != nil {
+			return Condition{}, err
+		}
+		resolved.OptionID = &option
+	case OperatorAnswered, OperatorNotAnswered:
+		if hasOption || condition.Value != "" {
+			return Condition{}, fmt.Errorf("%w: %s takes no option or value", ErrInvalidLogic, condition.Operator)
+		}
+	case OperatorEquals:
+		if HasOptions(string(q.QuestionType)) {
+			return Condition{}, fmt.Errorf("%w: use selected to test a select or multiselect question", ErrInvalidLogic)
+		}
+		if hasOption || condition.Value == "" {
+			return Condition{}, fmt.Errorf("%w: equals needs a value and no option", ErrInvalidLogic)
+		}
+		resolved.Value = condition.Value
+	default:
+		return Condition{}, fmt.Errorf("%w: unknown operator %q", ErrInvalidLogic, condition.Operator)
+	}
+
+	return resolved, nil
+}
+
+func findQuestion(questions []OptionsQuestion, id *uuid.UUID, index *int) (int, error) {
+	switch {
+	case id != nil && index != nil:
+		return 0, fmt.Errorf("%w: give either a question ID or a question index", ErrInvalidLogic)
+	case id != nil:
+		for i, q := range questions {
+			if q.QuestionID == *id {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: question %s is not part of the form", ErrInvalidLogic, *id)
+	case index != nil:
+		if *index < 0 || *index >= len(questions) {
+			return 0, fmt.Errorf("%w: question index %d is out of range", ErrInvalidLogic, *index)
+		}
+		return *index, nil
+	default:
+		return 0, fmt.Errorf("%w: a question ID or question index is required", ErrInvalidLogic)
+	}
+}
+
+func findSection(sections []uuid.UUID, id *uuid.UUID, index *int) (int, error) {
+	switch {
+	case id != nil && index != nil:
+		return 0, fmt.Errorf("%w: give either a section ID or a section index", ErrInvalidLogic)
+	case id != nil:
+		for i, sectionID := range sections {
+			if sectionID == *id {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: section %s is not part of the form", ErrInvalidLogic, *id)
+	default:
+		if *index < 0 || *index >= len(sections) {
+			return 0, fmt.Errorf("%w: section index %d is out of range", ErrInvalidLogic, *index)
+		}
+		return *index, nil
+	}
+}
+
+// FirstInSection returns the index of the first of the ordered questions that
+// belongs to the section, or -1 when none does.
+func FirstInSection(questions []OptionsQuestion, sectionID uuid.UUID) int {
+	for i, q := range questions {
+		if q.SectionID != nil && *q.SectionID == sectionID {
+			return i
+		}
+	}
+	return -1
+}
+
+func findOption(q OptionsQuestion, id *uuid.UUID, index *int) (uuid.UUID, error) {
+	switch {
+	case id != nil && index != nil:
+		return uuid.Nil, fmt.Errorf("%w: give either an option ID or an option index", ErrInvalidLogic)
+	case id != nil:
+		for _, option := range q.Options {
+			if option.OptionID == *id {
+				return option.OptionID, nil
+			}
+		}
+		return uuid.Nil, fmt.Errorf("%w: option %s does not belong to the question", ErrInvalidLogic, *id)
+	case index != nil:
+		if *index < 0 || *index >= len(q.Options) {
+			return uuid.Nil, fmt.Errorf("%w: option index %d is out of range", ErrInvalidLogic, *index)
+		}
+		return q.Options[*index].OptionID, nil
+	default:
+		return uuid.Nil, fmt.Errorf("%w: an option ID or option index is required", ErrInvalidLogic)
+	}
+}
+
+// IndexLogic is the reverse of ResolveLogic: it returns the logic with every
+// question, option and section ID replaced by its index among questions and
+// sections, so it can be applied to a copy of the form whose questions and
+// sections get new IDs.
+func IndexLogic(logic *Logic, questions []OptionsQuestion, sections []uuid.UUID) *Logic {
+	if logic.isEmpty() {
+		return nil
+	}
+
+	indexed := Logic{Match: logic.Match}
+	for _, condition := range logic.ShowIf {
+		indexed.ShowIf = append(indexed.ShowIf, indexCondition(condition, questions))
+	}
+	for _, jump := range logic.Jumps {
+		j := Jump{If: indexCondition(jump.If, questions), End: jump.End}
+		j.ToQuestionID, j.ToQuestionIndex = indexQuestion(jump.ToQuestionID, jump.ToQuestionIndex, questions)
+		j.ToSectionID, j.ToSectionIndex = indexSection(jump.ToSectionID, jump.ToSectionIndex, sections)
+		indexed.Jumps = append(indexed.Jumps, j)
+	}
+
+	return &indexed
+}
+
+func indexCondition(condition Condition, questions []OptionsQuestion) Condition {
+	indexed := Condition{Operator: condition.Operator, Value: condition.Value}
+	indexed.QuestionID, indexed.QuestionIndex = indexQuestion(condition.QuestionID, condition.QuestionIndex, questions)
+	indexed.OptionID, indexed.OptionIndex = condition.OptionID, condition.OptionIndex
+
+	if condition.OptionID != nil && indexed.QuestionIndex != nil {
+		for i, option := range questions[*indexed.QuestionIndex].Options {
+			if option.OptionID == *condition.OptionID {
+				indexed.OptionID, indexed.OptionIndex = nil, &i
+				break
+			}
+		}
+	}
+
+	return indexed
+}
+
+func indexQuestion(id *uuid.UUID, index *int, questions []OptionsQuestion) (*uuid.UUID, *int) {
+	if id == nil {
+		return nil, index
+	}
+	for i, q := range questions {
+		if q.QuestionID == *id {
+			return nil, &i
+		}
+	}
+	return id, index
+}
+
+func indexSection(id *uuid.UUID, index *int, sections []uuid.UUID) (*uuid.UUID, *int) {
+	if id == nil {
+		return nil, index
+	}
+	for i, sectionID := range sections {
+		if sectionID == *id {
+			return nil, &i
+		}
+	}
+	return id, index
+}
+
+func encodeLogic(logic *Logic) ([]byte, error) {
+	if logic == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(logic)
+}
+
+func decodeLogic(data []byte) (*Logic, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var logic Logic
+	err := json.Unmarshal(data, &logic)
+	if err != nil {
+		return nil, err
+	}
+	if logic.isEmpty() {
+		return nil, nil
+	}
+	return &logic, nil
+}
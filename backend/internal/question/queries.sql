@@ -2,9 +2,51 @@
 SELECT *
 FROM questions
 WHERE form_id = $1
-ORDER BY created_at ASC;
+ORDER BY position ASC, created_at ASC;
 
 -- name: Create :one
-INSERT INTO questions (form_id, text, type, is_required)
-VALUES ($1, $2, $3, $4)
-RETURNING *;
\ No newline at end of file
+INSERT INTO questions (form_id, text, type, is_required, position, config, validation, section_id, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: Update :one
+UPDATE questions
+SET text        = $3,
+    type        = $4,
+    is_required = $5,
+    position    = $6,
+    config      = $7,
+    validation  = $8,
+    section_id  = $9,
+    description = $10,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2
+RETURNING *;
+
+-- name: UpdatePosition :exec
+UPDATE questions
+SET position   = $3,
+    section_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2;
+
+-- name: UpdateLogic :exec
+UPDATE questions
+SET logic      = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2;
+
+-- name: Delete :exec
+DELETE
+FROM questions
+WHERE id = $1
+  AND form_id = $2;
+
+-- name: GetByFormIDs :many
+SELECT *
+FROM questions
+WHERE form_id = ANY (@form_ids::uuid[])
+ORDER BY position ASC, created_at ASC;
@@ -1,4 +1,5 @@
-CREATE TYPE question_type AS ENUM ('short_answer', 'select', 'multiselect');
+CREATE TYPE question_type AS ENUM ('short_answer', 'select', 'multiselect', 'paragraph', 'number', 'email', 'url', 'date',
+    'time', 'rating', 'linear_scale');
 
 CREATE TABLE IF NOT EXISTS questions (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -7,5 +8,11 @@ CREATE TABLE IF NOT EXISTS questions (
     type question_type NOT NULL,
     is_required BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    position INTEGER NOT NULL DEFAULT 0,
+    config JSONB NOT NULL DEFAULT '{}',
+    validation JSONB NOT NULL DEFAULT '{}',
+    logic JSONB NOT NULL DEFAULT '{}',
+    section_id UUID REFERENCES sections(id) ON DELETE SET NULL,
+    description TEXT NOT NULL DEFAULT ''
 );
\ No newline at end of file
@@ -3,19 +3,36 @@ package question
 import (
 	"context"
 	"database-final-project/internal/options"
+	"errors"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+// MaxDescriptionLength bounds the Markdown help text of a question.
+const MaxDescriptionLength = 5000
+
 type Querier interface {
 	GetByFormID(ctx context.Context, formID uuid.UUID) ([]Question, error)
+	GetByFormIDs(ctx context.Context, formIds []uuid.UUID) ([]Question, error)
 	Create(ctx context.Context, arg CreateParams) (Question, error)
+	Update(ctx context.Context, arg UpdateParams) (Question, error)
+	UpdatePosition(ctx context.Context, arg UpdatePositionParams) error
+	UpdateLogic(ctx context.Context, arg UpdateLogicParams) error
+	Delete(ctx context.Context, arg DeleteParams) error
 }
 
 type optionStore interface {
-	Create(ctx context.Context, questionID uuid.UUID, text string) (options.Response, error)
+	Create(ctx context.Context, questionID uuid.UUID, text string, position int32) (options.Response, error)
 	GetByQuestionID(ctx context.Context, questionID uuid.UUID) ([]options.Response, error)
+	GetByQuestionIDs(ctx context.Context, questionIDs []uuid.UUID) (map[uuid.UUID][]options.Response, error)
+	Update(ctx context.Context, questionID uuid.UUID, id uuid.UUID, text string, position int32) (options.Response, error)
+	UpdatePosition(ctx context.Context, questionID uuid.UUID, id uuid.UUID, position int32) error
+	Delete(ctx context.Context, questionID uuid.UUID, id uuid.UUID) error
 }
 
 type Service struct {
@@ -38,30 +55,112 @@ func (s *Service) GetByFormID(ctx context.Context, formID uuid.UUID) ([]OptionsQ
 		return nil, err
 	}
 
-	var optionsQuestions []OptionsQuestion
+	return s.withOptions(ctx, question)
+}
+
+// GetByFormIDs loads the questions of several forms, grouped by form ID, in
+// a constant number of queries.
+func (s *Service) GetByFormIDs(ctx context.Context, formIDs []uuid.UUID) (map[uuid.UUID][]OptionsQuestion, error) {
+	question, err := s.queries.GetByFormIDs(ctx, formIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsQuestions, err := s.withOptions(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	formIDsByQuestion := make(map[uuid.UUID]uuid.UUID, len(question))
 	for _, q := range question {
-		os, err := s.optionStore.GetByQuestionID(ctx, q.ID)
+		formIDsByQuestion[q.ID] = q.FormID
+	}
+
+	questionsByForm := make(map[uuid.UUID][]OptionsQuestion, len(formIDs))
+	for _, q := range optionsQuestions {
+		formID := formIDsByQuestion[q.QuestionID]
+		questionsByForm[formID] = append(questionsByForm[formID], q)
+	}
+
+	return questionsByForm, nil
+}
+
+// withOptions attaches the options of all questions with a single query.
+func (s *Service) withOptions(ctx context.Context, question []Question) ([]OptionsQuestion, error) {
+	if len(question) == 0 {
+		return nil, nil
+	}
+
+	questionIDs := make([]uuid.UUID, len(question))
+	for i, q := range question {
+		questionIDs[i] = q.ID
+	}
+
+	optionsByQuestion, err := s.optionStore.GetByQuestionIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsQuestions := make([]OptionsQuestion, len(question))
+	for i, q := range question {
+		config, err := decodeConfig(q.Config)
+		if err != nil {
+			return nil, err
+		}
+		validation, err := decodeValidation(q.Validation)
 		if err != nil {
 			return nil, err
 		}
-		optionsQuestions = append(optionsQuestions, OptionsQuestion{
+		logic, err := decodeLogic(q.Logic)
+		if err != nil {
+			return nil, err
+		}
+		optionsQuestions[i] = OptionsQuestion{
 			QuestionID:   q.ID,
 			QuestionType: q.Type,
 			QuestionText: q.Text,
+			Description:  q.Description,
 			IsRequired:   q.IsRequired,
-			Options:      os,
-		})
+			Position:     q.Position,
+			Config:       config,
+			Validation:   validation,
+			Logic:        logic,
+			SectionID:    sectionIDPtr(q.SectionID),
+			Options:      optionsByQuestion[q.ID],
+		}
 	}
 
 	return optionsQuestions, nil
 }
 
-func (s *Service) Create(ctx context.Context, formID uuid.UUID, questionText string, questionType string, isRequired bool, optionsReq []string) (OptionsQuestion, error) {
+// Create inserts the question with its options into the section with
+// sectionID, or into no section when it is uuid.Nil. config and validation are
+// checked against the question type; config is stored with its defaults filled in.
+func (s *Service) Create(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, questionText string, description string, questionType string, isRequired bool, position int32, config *Config, validation *Validation, optionsReq []string) (OptionsQuestion, error) {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return OptionsQuestion{}, ErrDescriptionTooLong
+	}
+
+	config, encodedConfig, err := prepareConfig(questionType, config, len(optionsReq))
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
+	validation, encodedValidation, err := prepareValidation(questionType, validation)
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
 	question, err := s.queries.Create(ctx, CreateParams{
-		FormID:     formID,
-		Text:       questionText,
-		Type:       QuestionType(questionType),
-		IsRequired: isRequired,
+		FormID:      formID,
+		Text:        questionText,
+		Type:        QuestionType(questionType),
+		IsRequired:  isRequired,
+		Position:    position,
+		Config:      encodedConfig,
+		Validation:  encodedValidation,
+		SectionID:   nullSectionID(sectionID),
+		Description: description,
 	})
 	if err != nil {
 		return OptionsQuestion{}, err
@@ -69,7 +168,7 @@ func (s *Service) Create(ctx context.Context, formID uuid.UUID, questionText str
 
 	os := make([]options.Response, len(optionsReq))
 	for i, optionText := range optionsReq {
-		option, err := s.optionStore.Create(ctx, question.ID, optionText)
+		option, err := s.optionStore.Create(ctx, question.ID, optionText, int32(i))
 		if err != nil {
 			return OptionsQuestion{}, err
 		}
@@ -80,7 +179,230 @@ func (s *Service) Create(ctx context.Context, formID uuid.UUID, questionText str
 		QuestionID:   question.ID,
 		QuestionType: question.Type,
 		QuestionText: question.Text,
+		Description:  question.Description,
 		IsRequired:   question.IsRequired,
+		Position:     question.Position,
+		Config:       config,
+		Validation:   validation,
+		SectionID:    sectionIDPtr(question.SectionID),
 		Options:      os,
 	}, err
 }
+
+// prepareConfig validates the configuration and option count of a question
+// and encodes the normalized configuration for storage.
+func prepareConfig(questionType string, config *Config, optionCount int) (*Config, []byte, error) {
+	config, err := NormalizeConfig(questionType, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if optionCount > 0 && !HasOptions(questionType) {
+		return nil, nil, fmt.Errorf("%w: %s questions do not take options", ErrInvalidConfig, questionType)
+	}
+
+	encodedConfig, err := encodeConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, encodedConfig, nil
+}
+
+func prepareValidation(questionType string, validation *Validation) (*Validation, []byte, error) {
+	validation, err := NormalizeValidation(questionType, validation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encodedValidation, err := encodeValidation(validation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return validation, encodedValidation, nil
+}
+
+// Update changes the question in place, moving it to the section with
+// sectionID or out of any section when it is uuid.Nil, and reconciles its options with
+// optionsReq: options with an ID are updated, options without one are created
+// and existing options missing from optionsReq are deleted. Keeping the IDs
+// stable keeps existing answers linked to the question and its options.
+func (s *Service) Update(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, id uuid.UUID, questionText string, description string, questionType string, isRequired bool, position int32, config *Config, validation *Validation, optionsReq []options.Request) (OptionsQuestion, error) {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return OptionsQuestion{}, ErrDescriptionTooLong
+	}
+
+	config, encodedConfig, err := prepareConfig(questionType, config, len(optionsReq))
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
+	validation, encodedValidation, err := prepareValidation(questionType, validation)
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
+	question, err := s.queries.Update(ctx, UpdateParams{
+		ID:          id,
+		FormID:      formID,
+		Text:        questionText,
+		Type:        QuestionType(questionType),
+		IsRequired:  isRequired,
+		Position:    position,
+		Config:      encodedConfig,
+		Validation:  encodedValidation,
+		SectionID:   nullSectionID(sectionID),
+		Description: description,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OptionsQuestion{}, ErrQuestionNotFound
+		}
+		return OptionsQuestion{}, err
+	}
+
+	logic, err := decodeLogic(question.Logic)
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
+	existing, err := s.optionStore.GetByQuestionID(ctx, question.ID)
+	if err != nil {
+		return OptionsQuestion{}, err
+	}
+
+	kept := make(map[uuid.UUID]bool, len(existing))
+	for _, option := range existing {
+		kept[option.OptionID] = false
+	}
+
+	os := make([]options.Response, len(optionsReq))
+	for i, optionReq := range optionsReq {
+		var option options.Response
+		if optionReq.OptionID == uuid.Nil {
+			option, err = s.optionStore.Create(ctx, question.ID, optionReq.OptionText, int32(i))
+		} else if isKept, ok := kept[optionReq.OptionID]; ok && !isKept {
+			kept[optionReq.OptionID] = true
+			option, err = s.optionStore.Update(ctx, question.ID, optionReq.OptionID, optionReq.OptionText, int32(i))
+		} else {
+			err = options.ErrOptionNotFound
+		}
+		if err != nil {
+			return OptionsQuestion{}, err
+		}
+		os[i] = option
+	}
+
+	for optionID, isKept := range kept {
+		if isKept {
+			continue
+		}
+		err := s.optionStore.Delete(ctx, question.ID, optionID)
+		if err != nil {
+			return OptionsQuestion{}, err
+		}
+	}
+
+	return OptionsQuestion{
+		QuestionID:   question.ID,
+		QuestionType: question.Type,
+		QuestionText: question.Text,
+		Description:  question.Description,
+		IsRequired:   question.IsRequired,
+		Position:     question.Position,
+		Config:       config,
+		Validation:   validation,
+		Logic:        logic,
+		SectionID:    sectionIDPtr(question.SectionID),
+		Options:      os,
+	}, nil
+}
+
+// Reorder moves the question to position within the section with sectionID,
+// or outside any section when it is uuid.Nil, and, when optionIDs is not nil,
+// renumbers its options in the given order. optionIDs must list every option
+// of the question exactly once.
+func (s *Service) Reorder(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, id uuid.UUID, position int32, optionIDs []uuid.UUID) error {
+	err := s.queries.UpdatePosition(ctx, UpdatePositionParams{
+		ID:        id,
+		FormID:    formID,
+		Position:  position,
+		SectionID: nullSectionID(sectionID),
+	})
+	if err != nil {
+		return err
+	}
+
+	if optionIDs == nil {
+		return nil
+	}
+
+	existing, err := s.optionStore.GetByQuestionID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !isPermutation(existing, optionIDs) {
+		return ErrInvalidOptionOrder
+	}
+
+	for i, optionID := range optionIDs {
+		err := s.optionStore.UpdatePosition(ctx, id, optionID, int32(i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetLogic stores the logic of the question, replacing any earlier logic. The
+// logic must already be resolved with ResolveLogic; nil clears it.
+func (s *Service) SetLogic(ctx context.Context, formID uuid.UUID, id uuid.UUID, logic *Logic) error {
+	encodedLogic, err := encodeLogic(logic)
+	if err != nil {
+		return err
+	}
+
+	return s.queries.UpdateLogic(ctx, UpdateLogicParams{
+		ID:     id,
+		FormID: formID,
+		Logic:  encodedLogic,
+	})
+}
+
+func isPermutation(existing []options.Response, ids []uuid.UUID) bool {
+	if len(existing) != len(ids) {
+		return false
+	}
+
+	listed := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	for _, option := range existing {
+		if !listed[option.OptionID] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	return s.queries.Delete(ctx, DeleteParams{
+		ID:     id,
+		FormID: formID,
+	})
+}
+
+func nullSectionID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
+
+func sectionIDPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	sectionID := uuid.UUID(id.Bytes)
+	return &sectionID
+}
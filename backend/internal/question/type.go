@@ -6,10 +6,20 @@ import (
 	"github.com/google/uuid"
 )
 
+// OptionsQuestion is a question with its options. Description is help text
+// written in Markdown; DescriptionHTML holds it rendered and sanitized when a
+// client asks for HTML.
 type OptionsQuestion struct {
-	QuestionID   uuid.UUID          `json:"question_id"`
-	QuestionType QuestionType       `json:"type"`
-	QuestionText string             `json:"question_text"`
-	IsRequired   bool               `json:"is_required"`
-	Options      []options.Response `json:"options,omitempty"`
+	QuestionID      uuid.UUID          `json:"question_id"`
+	QuestionType    QuestionType       `json:"type"`
+	QuestionText    string             `json:"question_text"`
+	Description     string             `json:"description,omitempty"`
+	DescriptionHTML string             `json:"description_html,omitempty"`
+	IsRequired      bool               `json:"is_required"`
+	Position        int32              `json:"position"`
+	Config          *Config            `json:"config,omitempty"`
+	Validation      *Validation        `json:"validation,omitempty"`
+	Logic           *Logic             `json:"logic,omitempty"`
+	SectionID       *uuid.UUID         `json:"section_id,omitempty"`
+	Options         []options.Response `json:"options,omitempty"`
 }
@@ -0,0 +1,123 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// maxPatternLength bounds the size of a validation pattern.
+const maxPatternLength = 500
+
+// Validation holds optional constraints on the answers to a question. Length
+// and pattern rules apply to text questions, MinValue and MaxValue make a
+// short_answer question take a number in range, and the selection rules apply
+// to multiselect questions. Rules only apply to answered questions; use
+// is_required to demand an answer.
+type Validation struct {
+	MinLength *int `json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	MaxLength *int `json:"max_length,omitempty" yaml:"max_length,omitempty"`
+	// Pattern is a regular expression the whole answer has to match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// PatternMessage replaces the default error when Pattern does not match.
+	PatternMessage string   `json:"pattern_message,omitempty" yaml:"pattern_message,omitempty"`
+	MinValue       *float64 `json:"min_value,omitempty" yaml:"min_value,omitempty"`
+	MaxValue       *float64 `json:"max_value,omitempty" yaml:"max_value,omitempty"`
+	MinSelections  *int     `json:"min_selections,omitempty" yaml:"min_selections,omitempty"`
+	MaxSelections  *int     `json:"max_selections,omitempty" yaml:"max_selections,omitempty"`
+}
+
+func (v *Validation) isEmpty() bool {
+	return v == nil || *v == Validation{}
+}
+
+// PatternRegexp compiles Pattern anchored to the whole answer, or returns nil
+// when there is no pattern.
+func (v *Validation) PatternRegexp() (*regexp.Regexp, error) {
+	if v == nil || v.Pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(`^(?:` + v.Pattern + `)$`)
+}
+
+// NormalizeValidation checks that validation only holds rules that apply to
+// questionType and that they are consistent. It returns nil when there are no
+// rules and an error wrapping ErrInvalidValidation otherwise.
+func NormalizeValidation(questionType string, validation *Validation) (*Validation, error) {
+	if validation.isEmpty() {
+		return nil, nil
+	}
+
+	isText := false
+	switch QuestionType(questionType) {
+	case QuestionTypeShortAnswer, QuestionTypeParagraph, QuestionTypeEmail, QuestionTypeUrl:
+		isText = true
+	}
+
+	hasLength := validation.MinLength != nil || validation.MaxLength != nil || validation.Pattern != "" || validation.PatternMessage != ""
+	if hasLength && !isText {
+		return nil, fmt.Errorf("%w: %s questions do not take length or pattern rules", ErrInvalidValidation, questionType)
+	}
+	if (validation.MinValue != nil || validation.MaxValue != nil) && QuestionType(questionType) != QuestionTypeShortAnswer {
+		return nil, fmt.Errorf("%w: only short_answer questions take min_value and max_value", ErrInvalidValidation)
+	}
+	if (validation.MinSelections != nil || validation.MaxSelections != nil) && QuestionType(questionType) != QuestionTypeMultiselect {
+		return nil, fmt.Errorf("%w: only multiselect questions take min_selections and max_selections", ErrInvalidValidation)
+	}
+
+	if err := checkRange("length", validation.MinLength, validation.MaxLength); err != nil {
+		return nil, err
+	}
+	if err := checkRange("selections", validation.MinSelections, validation.MaxSelections); err != nil {
+		return nil, err
+	}
+	if validation.MinValue != nil && validation.MaxValue != nil && *validation.MinValue > *validation.MaxValue {
+		return nil, fmt.Errorf("%w: min_value must not be greater than max_value", ErrInvalidValidation)
+	}
+
+	if len(validation.Pattern) > maxPatternLength {
+		return nil, fmt.Errorf("%w: pattern must be at most %d characters", ErrInvalidValidation, maxPatternLength)
+	}
+	if validation.PatternMessage != "" && validation.Pattern == "" {
+		return nil, fmt.Errorf("%w: pattern_message needs a pattern", ErrInvalidValidation)
+	}
+	if _, err := regexp.Compile(validation.Pattern); err != nil {
+		return nil, fmt.Errorf("%w: pattern is not a valid regular expression: %v", ErrInvalidValidation, err)
+	}
+
+	normalized := *validation
+	return &normalized, nil
+}
+
+func checkRange(name string, low *int, high *int) error {
+	if (low != nil && *low < 0) || (high != nil && *high < 0) {
+		return fmt.Errorf("%w: min_%s and max_%s must not be negative", ErrInvalidValidation, name, name)
+	}
+	if low != nil && high != nil && *low > *high {
+		return fmt.Errorf("%w: min_%s must not be greater than max_%s", ErrInvalidValidation, name, name)
+	}
+	return nil
+}
+
+func encodeValidation(validation *Validation) ([]byte, error) {
+	if validation == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(validation)
+}
+
+func decodeValidation(data []byte) (*Validation, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var validation Validation
+	err := json.Unmarshal(data, &validation)
+	if err != nil {
+		return nil, err
+	}
+	if validation.isEmpty() {
+		return nil, nil
+	}
+	return &validation, nil
+}
@@ -0,0 +1,10 @@
+package internal
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes the LIKE wildcards in a user supplied search term.
+func EscapeLike(search string) string {
+	return likeEscaper.Replace(search)
+}
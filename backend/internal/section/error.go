@@ -0,0 +1,7 @@
+package section
+
+import "errors"
+
+var (
+	ErrSectionNotFound = errors.New("section not found")
+)
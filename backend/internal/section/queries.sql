@@ -0,0 +1,39 @@
+-- name: GetByFormID :many
+SELECT *
+FROM sections
+WHERE form_id = $1
+ORDER BY position ASC, created_at ASC;
+
+-- name: GetByFormIDs :many
+SELECT *
+FROM sections
+WHERE form_id = ANY (@form_ids::uuid[])
+ORDER BY position ASC, created_at ASC;
+
+-- name: Create :one
+INSERT INTO sections (form_id, title, description, position)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: Update :one
+UPDATE sections
+SET title       = $3,
+    description = $4,
+    position    = $5,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2
+RETURNING *;
+
+-- name: UpdatePosition :exec
+UPDATE sections
+SET position   = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2;
+
+-- name: Delete :exec
+DELETE
+FROM sections
+WHERE id = $1
+  AND form_id = $2;
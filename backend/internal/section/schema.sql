@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sections
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    title       VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT        NOT NULL DEFAULT '',
+    position    INTEGER     NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS sections_form_id_position_idx ON sections (form_id, position);
@@ -0,0 +1,117 @@
+package section
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type Querier interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]Section, error)
+	GetByFormIDs(ctx context.Context, formIds []uuid.UUID) ([]Section, error)
+	Create(ctx context.Context, arg CreateParams) (Section, error)
+	Update(ctx context.Context, arg UpdateParams) (Section, error)
+	UpdatePosition(ctx context.Context, arg UpdatePositionParams) error
+	Delete(ctx context.Context, arg DeleteParams) error
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (s *Service) GetByFormID(ctx context.Context, formID uuid.UUID) ([]Response, error) {
+	sections, err := s.queries.GetByFormID(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []Response
+	for _, section := range sections {
+		responses = append(responses, toResponse(section))
+	}
+
+	return responses, nil
+}
+
+// GetByFormIDs loads the sections of several forms in one query, grouped by
+// form ID.
+func (s *Service) GetByFormIDs(ctx context.Context, formIDs []uuid.UUID) (map[uuid.UUID][]Response, error) {
+	sections, err := s.queries.GetByFormIDs(ctx, formIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[uuid.UUID][]Response, len(formIDs))
+	for _, section := range sections {
+		responses[section.FormID] = append(responses[section.FormID], toResponse(section))
+	}
+
+	return responses, nil
+}
+
+func (s *Service) Create(ctx context.Context, formID uuid.UUID, title string, description string, position int32) (Response, error) {
+	section, err := s.queries.Create(ctx, CreateParams{
+		FormID:      formID,
+		Title:       title,
+		Description: description,
+		Position:    position,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return toResponse(section), nil
+}
+
+func (s *Service) Update(ctx context.Context, formID uuid.UUID, id uuid.UUID, title string, description string, position int32) (Response, error) {
+	section, err := s.queries.Update(ctx, UpdateParams{
+		ID:          id,
+		FormID:      formID,
+		Title:       title,
+		Description: description,
+		Position:    position,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Response{}, ErrSectionNotFound
+		}
+		return Response{}, err
+	}
+
+	return toResponse(section), nil
+}
+
+func (s *Service) UpdatePosition(ctx context.Context, formID uuid.UUID, id uuid.UUID, position int32) error {
+	return s.queries.UpdatePosition(ctx, UpdatePositionParams{
+		ID:       id,
+		FormID:   formID,
+		Position: position,
+	})
+}
+
+func (s *Service) Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	return s.queries.Delete(ctx, DeleteParams{
+		ID:     id,
+		FormID: formID,
+	})
+}
+
+func toResponse(section Section) Response {
+	return Response{
+		SectionID:   section.ID,
+		Title:       section.Title,
+		Description: section.Description,
+		Position:    section.Position,
+	}
+}
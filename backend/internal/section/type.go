@@ -0,0 +1,10 @@
+package section
+
+import "github.com/google/uuid"
+
+type Response struct {
+	SectionID   uuid.UUID `json:"section_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Position    int32     `json:"position"`
+}
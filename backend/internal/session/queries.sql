@@ -0,0 +1,15 @@
+-- name: GetByTokenHash :one
+SELECT s.id, s.user_id, s.expires_at, u.username
+FROM sessions s
+         JOIN users u ON u.id = s.user_id
+WHERE s.token_hash = $1
+  AND s.expires_at > CURRENT_TIMESTAMP;
+
+-- name: Create :one
+INSERT INTO sessions (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: DeleteByTokenHash :exec
+DELETE FROM sessions WHERE token_hash = $1;
+
+-- name: DeleteExpiredByUserID :exec
+DELETE FROM sessions WHERE user_id = $1 AND expires_at <= CURRENT_TIMESTAMP;
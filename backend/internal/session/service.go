@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"database-final-project/internal"
+	"database-final-project/internal/auth"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// sessionTTL is how long a session token stays valid after signing in.
+const sessionTTL = 7 * 24 * time.Hour
+
+type Querier interface {
+	GetByTokenHash(ctx context.Context, tokenHash []byte) (GetByTokenHashRow, error)
+	Create(ctx context.Context, arg CreateParams) (Session, error)
+	DeleteByTokenHash(ctx context.Context, tokenHash []byte) error
+	DeleteExpiredByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Create starts a session for the user and returns its bearer token. Only a
+// hash of the token is stored. Expired sessions of the user are cleaned up on
+// the way.
+func (s *Service) Create(ctx context.Context, userID uuid.UUID) (Response, error) {
+	err := s.queries.DeleteExpiredByUserID(ctx, userID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	token, err := internal.NewToken()
+	if err != nil {
+		return Response{}, err
+	}
+
+	session, err := s.queries.Create(ctx, CreateParams{
+		UserID:    userID,
+		TokenHash: internal.HashToken(token),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(sessionTTL), Valid: true},
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt.Time,
+	}, nil
+}
+
+// Authenticate returns the user of the unexpired session with the token, or
+// auth.ErrInvalidToken.
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.User, error) {
+	session, err := s.queries.GetByTokenHash(ctx, internal.HashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return auth.User{}, auth.ErrInvalidToken
+		}
+		return auth.User{}, err
+	}
+
+	return auth.User{
+		ID:       session.UserID,
+		Username: session.Username,
+	}, nil
+}
+
+// Revoke ends the session with the token. Unknown tokens are ignored.
+func (s *Service) Revoke(ctx context.Context, token string) error {
+	return s.queries.DeleteByTokenHash(ctx, internal.HashToken(token))
+}
@@ -0,0 +1,8 @@
+package session
+
+import "time"
+
+type Response struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
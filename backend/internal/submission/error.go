@@ -0,0 +1,39 @@
+package submission
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSubmissionNotFound = errors.New("submission not found")
+	ErrAlreadyResponded   = errors.New("you have already responded to this form")
+
+	ErrInvalidIdempotencyKey = errors.New("Idempotency-Key must be at most 255 characters")
+	ErrIdempotencyKeyReused  = errors.New("Idempotency-Key was already used for a different request")
+
+	// errConcurrentRetry is returned when a retry with the same idempotency
+	// key was stored while the submission was being made.
+	errConcurrentRetry = errors.New("concurrent retry")
+)
+
+type QuestionError struct {
+	QuestionID uuid.UUID
+	Message    string
+}
+
+// ValidationError lists every answer that does not fit the form it was
+// submitted to.
+type ValidationError struct {
+	Errors []QuestionError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("submission has %d invalid answer(s)", len(e.Errors))
+}
+
+func (e *ValidationError) add(questionID uuid.UUID, message string) {
+	e.Errors = append(e.Errors, QuestionError{QuestionID: questionID, Message: message})
+}
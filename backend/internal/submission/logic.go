@@ -0,0 +1,93 @@
+package submission
+
+import (
+	"database-final-project/internal/answer"
+	"database-final-project/internal/question"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// visibleQuestions walks the questions in form order and reports which of
+// them the respondent is shown given the answers. A question is hidden when
+// its show_if conditions do not hold or when a jump from an earlier question
+// skips over it, whether to a later question or to the first question of a
+// later section. Conditions only see answers to questions that are shown, so
+// answers left behind on a hidden question do not change the rest of the form.
+func visibleQuestions(questions []question.OptionsQuestion, answers map[uuid.UUID]answer.Request) map[uuid.UUID]bool {
+	positions := make(map[uuid.UUID]int, len(questions))
+	for i, q := range questions {
+		positions[q.QuestionID] = i
+	}
+
+	visible := make(map[uuid.UUID]bool, len(questions))
+	next := 0
+	for i, q := range questions {
+		if i < next || !isShown(q.Logic, answers, visible) {
+			continue
+		}
+		visible[q.QuestionID] = true
+
+		if q.Logic == nil {
+			continue
+		}
+		for _, jump := range q.Logic.Jumps {
+			if !holds(jump.If, answers, visible) {
+				continue
+			}
+			switch {
+			case jump.End:
+				next = len(questions)
+			case jump.ToSectionID != nil:
+				if target := question.FirstInSection(questions, *jump.ToSectionID); target >= 0 {
+					next = target
+				}
+			case jump.ToQuestionID != nil:
+				if target, ok := positions[*jump.ToQuestionID]; ok {
+					next = target
+				}
+			}
+			break
+		}
+	}
+
+	return visible
+}
+
+func isShown(logic *question.Logic, answers map[uuid.UUID]answer.Request, visible map[uuid.UUID]bool) bool {
+	if logic == nil || len(logic.ShowIf) == 0 {
+		return true
+	}
+
+	matchAny := logic.MatchesAny()
+	for _, condition := range logic.ShowIf {
+		if holds(condition, answers, visible) == matchAny {
+			return matchAny
+		}
+	}
+	return !matchAny
+}
+
+func holds(condition question.Condition, answers map[uuid.UUID]answer.Request, visible map[uuid.UUID]bool) bool {
+	if condition.QuestionID == nil {
+		return false
+	}
+
+	answerReq, ok := answers[*condition.QuestionID]
+	answered := ok && visible[*condition.QuestionID] && isAnswered(answerReq)
+
+	switch condition.Operator {
+	case question.OperatorSelected, question.OperatorNotSelected:
+		selected := answered && condition.OptionID != nil && slices.Contains(answerReq.AnswerOptions, *condition.OptionID)
+		return selected == (condition.Operator == question.OperatorSelected)
+	case question.OperatorAnswered:
+		return answered
+	case question.OperatorNotAnswered:
+		return !answered
+	case question.OperatorEquals:
+		return answered && strings.EqualFold(strings.TrimSpace(answerReq.AnswerText), strings.TrimSpace(condition.Value))
+	default:
+		return false
+	}
+}
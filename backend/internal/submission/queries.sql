@@ -1,9 +1,68 @@
--- name: GetByFormID :many
+-- name: ListByFormID :many
+SELECT s.*
+FROM submissions s
+WHERE s.form_id = @form_id
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR s.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR s.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('question_id')::uuid IS NULL OR EXISTS (SELECT 1
+                                                          FROM answers a
+                                                          WHERE a.submission_id = s.id
+                                                            AND a.question_id = sqlc.narg('question_id')
+                                                            AND (sqlc.narg('option_id')::uuid IS NULL OR
+                                                                 sqlc.narg('option_id') = ANY (a.answer_options))
+                                                            AND (sqlc.narg('text_contains')::text IS NULL OR
+                                                                 a.answer_text ILIKE '%' || sqlc.narg('text_contains') || '%')))
+  AND (NOT @has_cursor::bool
+    OR (NOT @descending::bool AND (s.created_at, s.id) > (@cursor_created_at::timestamptz, @cursor_id::uuid))
+    OR (@descending::bool AND (s.created_at, s.id) < (@cursor_created_at::timestamptz, @cursor_id::uuid)))
+ORDER BY CASE WHEN @descending::bool THEN s.created_at END DESC,
+         CASE WHEN @descending::bool THEN s.id END DESC,
+         s.created_at ASC,
+         s.id ASC
+LIMIT @page_size;
+
+-- name: CountByFormID :one
+SELECT COUNT(*)
+FROM submissions s
+WHERE s.form_id = @form_id
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR s.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR s.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('question_id')::uuid IS NULL OR EXISTS (SELECT 1
+                                                          FROM answers a
+                                                          WHERE a.submission_id = s.id
+                                                            AND a.question_id = sqlc.narg('question_id')
+                                                            AND (sqlc.narg('option_id')::uuid IS NULL OR
+                                                                 sqlc.narg('option_id') = ANY (a.answer_options))
+                                                            AND (sqlc.narg('text_contains')::text IS NULL OR
+                                                                 a.answer_text ILIKE '%' || sqlc.narg('text_contains') || '%')));
+
+-- name: Create :one
+INSERT INTO submissions (form_id, form_version_id, respondent_id, respondent_email, respondent_key, invitation_id,
+                         idempotency_key, request_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetByIdempotencyKey :one
 SELECT *
 FROM submissions
-WHERE form_id = $1;
+WHERE form_id = $1
+  AND idempotency_key = $2;
 
--- name: Create :one
-INSERT INTO submissions (form_id)
-VALUES ($1)
-RETURNING *;
\ No newline at end of file
+-- name: GetByID :one
+SELECT *
+FROM submissions
+WHERE id = $1
+  AND form_id = $2;
+
+-- name: Touch :one
+UPDATE submissions
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND form_id = $2
+RETURNING *;
+
+-- name: Delete :execrows
+DELETE
+FROM submissions
+WHERE id = $1
+  AND form_id = $2;
@@ -2,5 +2,16 @@ CREATE TABLE IF NOT EXISTS submissions (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     form_id UUID NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    form_version_id UUID NOT NULL REFERENCES form_versions (id) ON DELETE CASCADE,
+    respondent_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    respondent_email VARCHAR(320),
+    respondent_key TEXT,
+    invitation_id UUID REFERENCES form_invitations (id) ON DELETE SET NULL,
+    idempotency_key VARCHAR(255),
+    request_hash BYTEA,
+    CONSTRAINT submissions_form_id_respondent_key_key UNIQUE (form_id, respondent_key),
+    CONSTRAINT submissions_form_id_idempotency_key_key UNIQUE (form_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS submissions_form_id_created_at_id_idx ON submissions (form_id, created_at, id);
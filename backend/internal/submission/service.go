@@ -1,67 +1,428 @@
 package submission
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"database-final-project/internal"
 	"database-final-project/internal/answer"
+	"database-final-project/internal/question"
+	"database-final-project/internal/version"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+// maxIdempotencyKeyLength is the longest key idempotency_key holds.
+const maxIdempotencyKeyLength = 255
+
 type Querier interface {
-	GetByFormID(ctx context.Context, id uuid.UUID) ([]Submission, error)
-	Create(ctx context.Context, formID uuid.UUID) (Submission, error)
+	ListByFormID(ctx context.Context, arg ListByFormIDParams) ([]Submission, error)
+	CountByFormID(ctx context.Context, arg CountByFormIDParams) (int64, error)
+	GetByID(ctx context.Context, arg GetByIDParams) (Submission, error)
+	Create(ctx context.Context, arg CreateParams) (Submission, error)
+	GetByIdempotencyKey(ctx context.Context, arg GetByIdempotencyKeyParams) (Submission, error)
+	Touch(ctx context.Context, arg TouchParams) (Submission, error)
+	Delete(ctx context.Context, arg DeleteParams) (int64, error)
 }
 
 type answerStore interface {
-	GetBySubmissionID(ctx context.Context, submissionID uuid.UUID) ([]answer.Response, error)
-	Create(ctx context.Context, submissionID uuid.UUID, questionID uuid.UUID, answerText string, answerOptions []uuid.UUID) error
+	GetBySubmissionIDs(ctx context.Context, submissionIDs []uuid.UUID, questionsBySubmission map[uuid.UUID][]question.OptionsQuestion) (map[uuid.UUID][]answer.Response, error)
+	Create(ctx context.Context, submissionID uuid.UUID, questionID uuid.UUID, answerText string, answerOptions []uuid.UUID, value answer.Value) error
+	DeleteBySubmissionID(ctx context.Context, submissionID uuid.UUID) error
+}
+
+type versionStore interface {
+	GetLatest(ctx context.Context, formID uuid.UUID) (version.Response, error)
+	GetByID(ctx context.Context, id uuid.UUID) (version.Response, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]version.Response, error)
+}
+
+// formStore decides whether a form takes new submissions, and what it records
+// about the respondent.
+type formStore interface {
+	CheckAcceptingResponses(ctx context.Context, formID uuid.UUID, respondent Respondent) (Respondent, error)
+}
+
+type transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Service struct {
-	logger      *zap.Logger
-	queries     Querier
-	answerStore answerStore
+	logger       *zap.Logger
+	queries      Querier
+	transactor   transactor
+	answerStore  answerStore
+	versionStore versionStore
+	formStore    formStore
 }
 
-func NewService(logger *zap.Logger, queries Querier, answerStore answerStore) *Service {
+func NewService(logger *zap.Logger, queries Querier, transactor transactor, answerStore answerStore, versionStore versionStore, formStore formStore) *Service {
 	return &Service{
-		logger:      logger,
-		queries:     queries,
-		answerStore: answerStore,
+		logger:       logger,
+		queries:      queries,
+		transactor:   transactor,
+		answerStore:  answerStore,
+		versionStore: versionStore,
+		formStore:    formStore,
 	}
 }
 
-func (s *Service) GetByID(ctx context.Context, id uuid.UUID) ([]AnswersSubmission, error) {
-	submissions, err := s.queries.GetByFormID(ctx, id)
+// cursor marks the last submission of a page. It is only valid for the sort
+// direction it was created with.
+type cursor struct {
+	Descending bool      `json:"d"`
+	CreatedAt  time.Time `json:"t"`
+	ID         uuid.UUID `json:"id"`
+}
+
+// List returns one page of the form's submissions ordered by submission time,
+// each rendered against the form version it was submitted to.
+func (s *Service) List(ctx context.Context, formID uuid.UUID, params ListParams) (ListResponse, error) {
+	return s.list(ctx, formID, params, true)
+}
+
+// ListBatch is List without Total. Counting scans every matching submission,
+// so walking all pages of a large form with List would count them once per
+// page.
+func (s *Service) ListBatch(ctx context.Context, formID uuid.UUID, params ListParams) (ListResponse, error) {
+	return s.list(ctx, formID, params, false)
+}
+
+func (s *Service) list(ctx context.Context, formID uuid.UUID, params ListParams, withTotal bool) (ListResponse, error) {
+	var c cursor
+	if params.Cursor != "" {
+		err := internal.DecodeCursor(params.Cursor, &c)
+		if err != nil {
+			return ListResponse{}, err
+		}
+		if c.Descending != params.Descending {
+			return ListResponse{}, internal.ErrInvalidCursor
+		}
+	}
+
+	filter := CountByFormIDParams{
+		FormID:      formID,
+		CreatedFrom: pgtype.Timestamptz{Time: params.CreatedFrom, Valid: !params.CreatedFrom.IsZero()},
+		CreatedTo:   pgtype.Timestamptz{Time: params.CreatedTo, Valid: !params.CreatedTo.IsZero()},
+	}
+	if params.QuestionID != uuid.Nil {
+		filter.QuestionID = pgtype.UUID{Bytes: params.QuestionID, Valid: true}
+		filter.OptionID = pgtype.UUID{Bytes: params.OptionID, Valid: params.OptionID != uuid.Nil}
+		filter.TextContains = pgtype.Text{String: internal.EscapeLike(params.TextContains), Valid: params.TextContains != ""}
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	submissions, err := s.queries.ListByFormID(ctx, ListByFormIDParams{
+		FormID:          filter.FormID,
+		CreatedFrom:     filter.CreatedFrom,
+		CreatedTo:       filter.CreatedTo,
+		QuestionID:      filter.QuestionID,
+		OptionID:        filter.OptionID,
+		TextContains:    filter.TextContains,
+		HasCursor:       params.Cursor != "",
+		Descending:      params.Descending,
+		CursorCreatedAt: pgtype.Timestamptz{Time: c.CreatedAt, Valid: params.Cursor != ""},
+		CursorID:        c.ID,
+		PageSize:        params.Limit + 1,
+	})
 	if err != nil {
-		return nil, err
+		return ListResponse{}, err
+	}
+
+	var total int64
+	if withTotal {
+		total, err = s.queries.CountByFormID(ctx, filter)
+		if err != nil {
+			return ListResponse{}, err
+		}
 	}
 
+	var nextCursor string
+	if len(submissions) > int(params.Limit) {
+		submissions = submissions[:params.Limit]
+		last := submissions[len(submissions)-1]
+		nextCursor = internal.EncodeCursor(cursor{Descending: params.Descending, CreatedAt: last.CreatedAt.Time, ID: last.ID})
+	}
+
+	answersSubmissions, err := s.render(ctx, submissions)
+	if err != nil {
+		return ListResponse{}, err
+	}
+
+	return ListResponse{
+		Submissions: answersSubmissions,
+		NextCursor:  nextCursor,
+		Total:       total,
+	}, nil
+}
+
+// render loads the answers of the submissions and renders each of them
+// against the form version it was submitted to.
+func (s *Service) render(ctx context.Context, submissions []Submission) ([]AnswersSubmission, error) {
 	answersSubmissions := make([]AnswersSubmission, len(submissions))
+	if len(submissions) == 0 {
+		return answersSubmissions, nil
+	}
+
+	// Only the versions the submissions were made against are loaded; a page
+	// rarely spans more than a few of them.
+	var versionIDs []uuid.UUID
+	for _, submission := range submissions {
+		if !slices.Contains(versionIDs, submission.FormVersionID) {
+			versionIDs = append(versionIDs, submission.FormVersionID)
+		}
+	}
+
+	versions, err := s.versionStore.GetByIDs(ctx, versionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	versionsByID := make(map[uuid.UUID]version.Response, len(versions))
+	for _, v := range versions {
+		versionsByID[v.VersionID] = v
+	}
+
+	submissionIDs := make([]uuid.UUID, len(submissions))
+	questionsBySubmission := make(map[uuid.UUID][]question.OptionsQuestion, len(submissions))
+	for i, submission := range submissions {
+		submissionIDs[i] = submission.ID
+		questionsBySubmission[submission.ID] = versionsByID[submission.FormVersionID].Questions
+	}
+
+	answersBySubmission, err := s.answerStore.GetBySubmissionIDs(ctx, submissionIDs, questionsBySubmission)
+	if err != nil {
+		return nil, err
+	}
+
 	for i, submission := range submissions {
-		answers, err := s.answerStore.GetBySubmissionID(ctx, submission.ID)
+		answersSubmissions[i] = AnswersSubmission{
+			SubmissionID:    submission.ID,
+			FormVersion:     versionsByID[submission.FormVersionID].Version,
+			RespondentEmail: submission.RespondentEmail.String,
+			CreatedAt:       submission.CreatedAt.Time,
+			UpdatedAt:       submission.UpdatedAt.Time,
+			Answers:         answersBySubmission[submission.ID],
+		}
+		if submission.RespondentID.Valid {
+			respondentID := uuid.UUID(submission.RespondentID.Bytes)
+			answersSubmissions[i].RespondentID = &respondentID
+		}
+		if submission.InvitationID.Valid {
+			invitationID := uuid.UUID(submission.InvitationID.Bytes)
+			answersSubmissions[i].InvitationID = &invitationID
+		}
+	}
+
+	return answersSubmissions, nil
+}
+
+// Create validates the answers against the latest version of the form and
+// stores the submission with all of its answers in one transaction. The form
+// store is asked first whether the form takes the submission at all, and what
+// it records about the respondent. Invalid answers are reported as a
+// *ValidationError, a second response where only one is allowed as
+// ErrAlreadyResponded.
+//
+// With an idempotency key, a retry of a stored submission is not stored again
+// and reports replayed instead, even when the form no longer takes responses.
+// Reusing the key for a different request, or as a different respondent, is
+// ErrIdempotencyKeyReused.
+func (s *Service) Create(ctx context.Context, formID uuid.UUID, respondent Respondent, idempotency Idempotency, answerReqs []answer.Request) (replayed bool, err error) {
+	if len(idempotency.Key) > maxIdempotencyKeyLength {
+		return false, ErrInvalidIdempotencyKey
+	}
+
+	var requestHash []byte
+	if idempotency.Key != "" {
+		requestHash, err = hashRequest(idempotency.Request, respondent)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
-		answersSubmissions[i] = AnswersSubmission{
-			SubmissionID: submission.ID,
-			Answers:      answers,
+
+		replayed, err = s.replay(ctx, formID, idempotency.Key, requestHash)
+		if replayed || err != nil {
+			return replayed, err
 		}
+	}
 
+	err = s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		recorded, err := s.formStore.CheckAcceptingResponses(ctx, formID, respondent)
+		if err != nil {
+			return err
+		}
+
+		formVersion, err := s.versionStore.GetLatest(ctx, formID)
+		if err != nil {
+			return err
+		}
+
+		validated, err := validate(formVersion.Questions, answerReqs)
+		if err != nil {
+			return err
+		}
+
+		submission, err := s.queries.Create(ctx, CreateParams{
+			FormID:          formID,
+			FormVersionID:   formVersion.VersionID,
+			RespondentID:    nullUUID(recorded.UserID),
+			RespondentEmail: pgtype.Text{String: recorded.Email, Valid: recorded.Email != ""},
+			RespondentKey:   pgtype.Text{String: recorded.Key, Valid: recorded.Key != ""},
+			InvitationID:    nullUUID(recorded.InvitationID),
+			IdempotencyKey:  pgtype.Text{String: idempotency.Key, Valid: idempotency.Key != ""},
+			RequestHash:     requestHash,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.ConstraintName == "submissions_form_id_respondent_key_key" {
+				return ErrAlreadyResponded
+			}
+			if errors.As(err, &pgErr) && pgErr.ConstraintName == "submissions_form_id_idempotency_key_key" {
+				return errConcurrentRetry
+			}
+			return err
+		}
+
+		return s.createAnswers(ctx, submission.ID, validated)
+	})
+	if errors.Is(err, errConcurrentRetry) {
+		// Another attempt with the same key was stored while this one ran.
+		return s.replay(ctx, formID, idempotency.Key, requestHash)
 	}
 
-	return answersSubmissions, nil
+	return false, err
+}
+
+// hashRequest hashes the request body together with who sent it. Keys are
+// unique per form, so another respondent or invitation using a stored key is
+// told the key was reused instead of being shown as the first submission.
+func hashRequest(request []byte, respondent Respondent) ([]byte, error) {
+	body, err := json.Marshal(struct {
+		Request    []byte
+		UserID     *uuid.UUID
+		Email      string
+		Invitation string
+	}{
+		Request:    request,
+		UserID:     respondent.UserID,
+		Email:      respondent.Email,
+		Invitation: respondent.Invitation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(body)
+	return hash[:], nil
+}
+
+// replay reports whether a submission with the idempotency key was already
+// stored for the same request.
+func (s *Service) replay(ctx context.Context, formID uuid.UUID, key string, requestHash []byte) (bool, error) {
+	submission, err := s.queries.GetByIdempotencyKey(ctx, GetByIdempotencyKeyParams{
+		FormID:         formID,
+		IdempotencyKey: pgtype.Text{String: key, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !bytes.Equal(submission.RequestHash, requestHash) {
+		return false, ErrIdempotencyKeyReused
+	}
+
+	return true, nil
+}
+
+func (s *Service) Get(ctx context.Context, formID uuid.UUID, id uuid.UUID) (AnswersSubmission, error) {
+	submission, err := s.queries.GetByID(ctx, GetByIDParams{
+		ID:     id,
+		FormID: formID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AnswersSubmission{}, ErrSubmissionNotFound
+		}
+		return AnswersSubmission{}, err
+	}
+
+	answersSubmissions, err := s.render(ctx, []Submission{submission})
+	if err != nil {
+		return AnswersSubmission{}, err
+	}
+
+	return answersSubmissions[0], nil
 }
 
-func (s *Service) Create(ctx context.Context, formID uuid.UUID, answerReqs []answer.Request) error {
-	submission, err := s.queries.Create(ctx, formID)
+// Update replaces the whole answer set of a submission in one transaction.
+// The answers are validated against the form version the submission was
+// originally made against, so it keeps rendering consistently.
+func (s *Service) Update(ctx context.Context, formID uuid.UUID, id uuid.UUID, answerReqs []answer.Request) (AnswersSubmission, error) {
+	err := s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		submission, err := s.queries.Touch(ctx, TouchParams{
+			ID:     id,
+			FormID: formID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSubmissionNotFound
+			}
+			return err
+		}
+
+		formVersion, err := s.versionStore.GetByID(ctx, submission.FormVersionID)
+		if err != nil {
+			return err
+		}
+
+		validated, err := validate(formVersion.Questions, answerReqs)
+		if err != nil {
+			return err
+		}
+
+		err = s.answerStore.DeleteBySubmissionID(ctx, submission.ID)
+		if err != nil {
+			return err
+		}
+
+		return s.createAnswers(ctx, submission.ID, validated)
+	})
+	if err != nil {
+		return AnswersSubmission{}, err
+	}
+
+	return s.Get(ctx, formID, id)
+}
+
+func (s *Service) Delete(ctx context.Context, formID uuid.UUID, id uuid.UUID) error {
+	deleted, err := s.queries.Delete(ctx, DeleteParams{
+		ID:     id,
+		FormID: formID,
+	})
 	if err != nil {
 		return err
 	}
+	if deleted == 0 {
+		return ErrSubmissionNotFound
+	}
+
+	return nil
+}
 
+func (s *Service) createAnswers(ctx context.Context, submissionID uuid.UUID, answerReqs []answer.Request) error {
 	for _, answerReq := range answerReqs {
-		err := s.answerStore.Create(ctx, submission.ID, answerReq.QuestionID, answerReq.AnswerText, answerReq.AnswerOptions)
+		err := s.answerStore.Create(ctx, submissionID, answerReq.QuestionID, answerReq.AnswerText, answerReq.AnswerOptions, answerReq.Value)
 		if err != nil {
 			return err
 		}
@@ -69,3 +430,10 @@ func (s *Service) Create(ctx context.Context, formID uuid.UUID, answerReqs []ans
 
 	return nil
 }
+
+func nullUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
@@ -2,11 +2,61 @@ package submission
 
 import (
 	"database-final-project/internal/answer"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type AnswersSubmission struct {
-	SubmissionID uuid.UUID         `json:"submission_id"`
-	Answers      []answer.Response `json:"answers"`
+	SubmissionID    uuid.UUID         `json:"submission_id"`
+	FormVersion     int32             `json:"form_version"`
+	RespondentID    *uuid.UUID        `json:"respondent_id,omitempty"`
+	RespondentEmail string            `json:"respondent_email,omitempty"`
+	InvitationID    *uuid.UUID        `json:"invitation_id,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Answers         []answer.Response `json:"answers"`
+}
+
+// Idempotency makes retried submissions safe: a submission made with the Key
+// of a stored one is answered as that one was instead of being stored again.
+// Keys are unique per form, not per respondent, so clients should pick a
+// random one for each response. Request is the request body as sent; its hash
+// together with the respondent tells a retry apart from a different request,
+// or someone else, reusing the key. An empty Key turns this off.
+type Idempotency struct {
+	Key     string
+	Request []byte
+}
+
+// Respondent is who made a submission, as far as the form records it. Key is
+// only set for forms limited to one response per respondent. Invitation is
+// the token an invite-only form was opened with, InvitationID the invitation
+// the submission used up.
+type Respondent struct {
+	UserID       *uuid.UUID
+	Email        string
+	Key          string
+	Invitation   string
+	InvitationID *uuid.UUID
+}
+
+// ListParams selects one page of a form's submissions. The zero value of
+// every filter disables it; OptionID and TextContains only apply together
+// with QuestionID.
+type ListParams struct {
+	Limit        int32
+	Cursor       string
+	Descending   bool
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	QuestionID   uuid.UUID
+	OptionID     uuid.UUID
+	TextContains string
+}
+
+type ListResponse struct {
+	Submissions []AnswersSubmission `json:"submissions"`
+	NextCursor  string              `json:"next_cursor,omitempty"`
+	Total       int64               `json:"total"`
 }
@@ -0,0 +1,262 @@
+package submission
+
+import (
+	"database-final-project/internal/answer"
+	"database-final-project/internal/question"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// validate checks the answers against the questions of the form they are
+// submitted to. It returns a *ValidationError describing every problem, or the
+// answers with typed answers parsed into their Value and their text normalized.
+// Questions hidden by question logic are not required, and answers to them
+// are dropped without being checked.
+func validate(questions []question.OptionsQuestion, answerReqs []answer.Request) ([]answer.Request, error) {
+	validationErr := &ValidationError{}
+
+	questionsByID := make(map[uuid.UUID]question.OptionsQuestion, len(questions))
+	for _, q := range questions {
+		questionsByID[q.QuestionID] = q
+	}
+
+	answers := make(map[uuid.UUID]answer.Request, len(answerReqs))
+	messages := make(map[uuid.UUID]string)
+	seen := make(map[uuid.UUID]bool, len(answerReqs))
+	for _, answerReq := range answerReqs {
+		q, ok := questionsByID[answerReq.QuestionID]
+		if !ok {
+			validationErr.add(answerReq.QuestionID, "question does not belong to this form")
+			continue
+		}
+		if seen[q.QuestionID] {
+			validationErr.add(q.QuestionID, "question is answered more than once")
+			continue
+		}
+		seen[q.QuestionID] = true
+
+		answerReq, message := validateAnswer(q, answerReq)
+		if message == "" {
+			message = validateRules(q, answerReq)
+		}
+		if message != "" {
+			messages[q.QuestionID] = message
+			continue
+		}
+		answers[q.QuestionID] = answerReq
+	}
+
+	visible := visibleQuestions(questions, answers)
+
+	validated := make([]answer.Request, 0, len(answers))
+	for _, q := range questions {
+		if !visible[q.QuestionID] {
+			continue
+		}
+		if message, ok := messages[q.QuestionID]; ok {
+			validationErr.add(q.QuestionID, message)
+			continue
+		}
+
+		answerReq, ok := answers[q.QuestionID]
+		if q.IsRequired && (!ok || !isAnswered(answerReq)) {
+			validationErr.add(q.QuestionID, "question is required")
+		}
+		if ok {
+			validated = append(validated, answerReq)
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return nil, validationErr
+	}
+	return validated, nil
+}
+
+func isAnswered(answerReq answer.Request) bool {
+	return strings.TrimSpace(answerReq.AnswerText) != "" || len(answerReq.AnswerOptions) > 0
+}
+
+func validateAnswer(q question.OptionsQuestion, answerReq answer.Request) (answer.Request, string) {
+	if question.HasOptions(string(q.QuestionType)) {
+		if answerReq.AnswerText != "" {
+			return answerReq, string(q.QuestionType) + " questions do not accept text"
+		}
+		if q.QuestionType == question.QuestionTypeSelect && len(answerReq.AnswerOptions) > 1 {
+			return answerReq, "select questions accept a single option"
+		}
+		return answerReq, validateOptions(q, answerReq.AnswerOptions)
+	}
+
+	if len(answerReq.AnswerOptions) > 0 {
+		return answerReq, string(q.QuestionType) + " questions do not accept options"
+	}
+
+	switch q.QuestionType {
+	case question.QuestionTypeShortAnswer, question.QuestionTypeParagraph:
+		return answerReq, ""
+	}
+
+	text := strings.TrimSpace(answerReq.AnswerText)
+	if text == "" {
+		answerReq.AnswerText = ""
+		return answerReq, ""
+	}
+
+	switch q.QuestionType {
+	case question.QuestionTypeNumber:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return answerReq, "answer must be a number"
+		}
+		if message := validateNumber(q.Config, number); message != "" {
+			return answerReq, message
+		}
+		answerReq.AnswerText = strconv.FormatFloat(number, 'f', -1, 64)
+		answerReq.Value.Number = &number
+	case question.QuestionTypeRating, question.QuestionTypeLinearScale:
+		low, high := q.ScaleBounds()
+		value, err := strconv.Atoi(text)
+		if err != nil || value < low || value > high {
+			return answerReq, fmt.Sprintf("answer must be a whole number between %d and %d", low, high)
+		}
+		number := float64(value)
+		answerReq.AnswerText = strconv.Itoa(value)
+		answerReq.Value.Number = &number
+	case question.QuestionTypeEmail:
+		address, err := mail.ParseAddress(text)
+		if err != nil || address.Address != text {
+			return answerReq, "answer must be an email address"
+		}
+		answerReq.AnswerText = text
+	case question.QuestionTypeUrl:
+		u, err := url.Parse(text)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return answerReq, "answer must be an http or https URL"
+		}
+		answerReq.AnswerText = text
+	case question.QuestionTypeDate:
+		date, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return answerReq, "answer must be a date formatted as YYYY-MM-DD"
+		}
+		answerReq.AnswerText = date.Format(time.DateOnly)
+		answerReq.Value.Date = &date
+	case question.QuestionTypeTime:
+		timeOfDay, err := parseTimeOfDay(text)
+		if err != nil {
+			return answerReq, "answer must be a time formatted as HH:MM or HH:MM:SS"
+		}
+		sinceMidnight := timeOfDay.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))
+		answerReq.AnswerText = timeOfDay.Format(time.TimeOnly)
+		answerReq.Value.Time = &sinceMidnight
+	}
+
+	return answerReq, ""
+}
+
+// validateRules applies the question's validation rules to an answer that
+// already fits the question type. Unanswered questions are left to the
+// required check.
+func validateRules(q question.OptionsQuestion, answerReq answer.Request) string {
+	rules := q.Validation
+	if rules == nil || !isAnswered(answerReq) {
+		return ""
+	}
+
+	if len(answerReq.AnswerOptions) > 0 {
+		selections := len(answerReq.AnswerOptions)
+		if rules.MinSelections != nil && selections < *rules.MinSelections {
+			return fmt.Sprintf("select at least %d options", *rules.MinSelections)
+		}
+		if rules.MaxSelections != nil && selections > *rules.MaxSelections {
+			return fmt.Sprintf("select at most %d options", *rules.MaxSelections)
+		}
+		return ""
+	}
+
+	text := answerReq.AnswerText
+	length := utf8.RuneCountInString(text)
+	if rules.MinLength != nil && length < *rules.MinLength {
+		return fmt.Sprintf("answer must be at least %d characters", *rules.MinLength)
+	}
+	if rules.MaxLength != nil && length > *rules.MaxLength {
+		return fmt.Sprintf("answer must be at most %d characters", *rules.MaxLength)
+	}
+
+	pattern, err := rules.PatternRegexp()
+	if err != nil || (pattern != nil && !pattern.MatchString(text)) {
+		if rules.PatternMessage != "" {
+			return rules.PatternMessage
+		}
+		return "answer does not match the expected format"
+	}
+
+	if rules.MinValue != nil || rules.MaxValue != nil {
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "answer must be a number"
+		}
+		return validateNumber(&question.Config{Min: rules.MinValue, Max: rules.MaxValue}, number)
+	}
+
+	return ""
+}
+
+func validateNumber(config *question.Config, number float64) string {
+	if config == nil {
+		return ""
+	}
+	if config.Min != nil && number < *config.Min {
+		return "answer must be at least " + strconv.FormatFloat(*config.Min, 'f', -1, 64)
+	}
+	if config.Max != nil && number > *config.Max {
+		return "answer must be at most " + strconv.FormatFloat(*config.Max, 'f', -1, 64)
+	}
+	if config.Step != nil {
+		base := 0.0
+		if config.Min != nil {
+			base = *config.Min
+		}
+		steps := (number - base) / *config.Step
+		if math.Abs(steps-math.Round(steps)) > 1e-9 {
+			return "answer must be a multiple of " + strconv.FormatFloat(*config.Step, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+func parseTimeOfDay(text string) (time.Time, error) {
+	timeOfDay, err := time.Parse("15:04", text)
+	if err == nil {
+		return timeOfDay, nil
+	}
+	return time.Parse(time.TimeOnly, text)
+}
+
+func validateOptions(q question.OptionsQuestion, answerOptions []uuid.UUID) string {
+	optionIDs := make(map[uuid.UUID]bool, len(q.Options))
+	for _, o := range q.Options {
+		optionIDs[o.OptionID] = true
+	}
+
+	selected := make(map[uuid.UUID]bool, len(answerOptions))
+	for _, id := range answerOptions {
+		if !optionIDs[id] {
+			return "option " + id.String() + " does not belong to this question"
+		}
+		if selected[id] {
+			return "option " + id.String() + " is selected more than once"
+		}
+		selected[id] = true
+	}
+	return ""
+}
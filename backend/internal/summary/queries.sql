@@ -0,0 +1,61 @@
+-- name: CountByDay :many
+SELECT (s.created_at AT TIME ZONE 'UTC')::date AS day,
+       COUNT(*)                                 AS count
+FROM submissions s
+WHERE s.form_id = $1
+GROUP BY day
+ORDER BY day ASC;
+
+-- name: CountAsked :many
+-- Counts, per question, the submissions whose form version contained it.
+SELECT q.question_id::uuid AS question_id,
+       COUNT(*)            AS count
+FROM submissions s
+         JOIN form_versions v ON v.id = s.form_version_id
+         CROSS JOIN LATERAL jsonb_to_recordset(v.definition) AS q(question_id uuid)
+WHERE s.form_id = $1
+GROUP BY q.question_id;
+
+-- name: CountAnswered :many
+SELECT a.question_id,
+       COUNT(*) AS count
+FROM answers a
+         JOIN submissions s ON s.id = a.submission_id
+WHERE s.form_id = $1
+  AND (a.answer_text IS NOT NULL OR cardinality(a.answer_options) > 0)
+GROUP BY a.question_id;
+
+-- name: CountOptions :many
+SELECT a.question_id,
+       o.option_id::uuid AS option_id,
+       COUNT(*)          AS count
+FROM answers a
+         JOIN submissions s ON s.id = a.submission_id
+         CROSS JOIN LATERAL unnest(a.answer_options) AS o(option_id)
+WHERE s.form_id = $1
+GROUP BY a.question_id, o.option_id;
+
+-- name: TopTextAnswers :many
+SELECT ranked.question_id,
+       ranked.answer_text::text AS answer_text,
+       ranked.count
+FROM (SELECT a.question_id,
+             a.answer_text,
+             COUNT(*)                                                                           AS count,
+             ROW_NUMBER() OVER (PARTITION BY a.question_id ORDER BY COUNT(*) DESC, a.answer_text) AS rank
+      FROM answers a
+               JOIN submissions s ON s.id = a.submission_id
+      WHERE s.form_id = @form_id
+        AND a.answer_text IS NOT NULL
+      GROUP BY a.question_id, a.answer_text) ranked
+WHERE ranked.rank <= @top_n::bigint
+ORDER BY ranked.question_id, ranked.rank;
+
+-- name: AverageNumbers :many
+SELECT a.question_id,
+       AVG(a.answer_number)::float8 AS average
+FROM answers a
+         JOIN submissions s ON s.id = a.submission_id
+WHERE s.form_id = $1
+  AND a.answer_number IS NOT NULL
+GROUP BY a.question_id;
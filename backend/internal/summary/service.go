@@ -0,0 +1,174 @@
+package summary
+
+import (
+	"context"
+	"database-final-project/internal/question"
+	"database-final-project/internal/version"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// topAnswersLimit is the number of distinct answer texts returned per question.
+const topAnswersLimit = 10
+
+type Querier interface {
+	CountByDay(ctx context.Context, formID uuid.UUID) ([]CountByDayRow, error)
+	CountAsked(ctx context.Context, formID uuid.UUID) ([]CountAskedRow, error)
+	CountAnswered(ctx context.Context, formID uuid.UUID) ([]CountAnsweredRow, error)
+	CountOptions(ctx context.Context, formID uuid.UUID) ([]CountOptionsRow, error)
+	TopTextAnswers(ctx context.Context, arg TopTextAnswersParams) ([]TopTextAnswersRow, error)
+	AverageNumbers(ctx context.Context, formID uuid.UUID) ([]AverageNumbersRow, error)
+}
+
+type versionStore interface {
+	GetLatest(ctx context.Context, formID uuid.UUID) (version.Response, error)
+}
+
+type Service struct {
+	logger       *zap.Logger
+	queries      Querier
+	versionStore versionStore
+}
+
+func NewService(logger *zap.Logger, queries Querier, versionStore versionStore) *Service {
+	return &Service{
+		logger:       logger,
+		queries:      queries,
+		versionStore: versionStore,
+	}
+}
+
+// Get aggregates the submissions of a form. All counting happens in the
+// database; only the latest question tree is used to label the results.
+func (s *Service) Get(ctx context.Context, formID uuid.UUID) (Response, error) {
+	formVersion, err := s.versionStore.GetLatest(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	days, err := s.queries.CountByDay(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	asked, err := s.queries.CountAsked(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	answered, err := s.queries.CountAnswered(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	optionCounts, err := s.queries.CountOptions(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	topAnswers, err := s.queries.TopTextAnswers(ctx, TopTextAnswersParams{
+		FormID: formID,
+		TopN:   topAnswersLimit,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	averages, err := s.queries.AverageNumbers(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	response := Response{
+		FormID:            formID,
+		ResponsesOverTime: make([]DailyCount, len(days)),
+		Questions:         make([]QuestionSummary, len(formVersion.Questions)),
+	}
+	for i, day := range days {
+		response.TotalResponses += day.Count
+		response.ResponsesOverTime[i] = DailyCount{
+			Date:  day.Day.Time.Format(time.DateOnly),
+			Count: day.Count,
+		}
+	}
+
+	askedByQuestion := make(map[uuid.UUID]int64, len(asked))
+	for _, row := range asked {
+		askedByQuestion[row.QuestionID] = row.Count
+	}
+
+	answeredByQuestion := make(map[uuid.UUID]int64, len(answered))
+	for _, row := range answered {
+		answeredByQuestion[row.QuestionID] = row.Count
+	}
+
+	optionsByQuestion := make(map[uuid.UUID]map[uuid.UUID]int64)
+	for _, row := range optionCounts {
+		if optionsByQuestion[row.QuestionID] == nil {
+			optionsByQuestion[row.QuestionID] = make(map[uuid.UUID]int64)
+		}
+		optionsByQuestion[row.QuestionID][row.OptionID] = row.Count
+	}
+
+	topByQuestion := make(map[uuid.UUID][]TextCount)
+	for _, row := range topAnswers {
+		topByQuestion[row.QuestionID] = append(topByQuestion[row.QuestionID], TextCount{
+			Text:  row.AnswerText,
+			Count: row.Count,
+		})
+	}
+
+	averageByQuestion := make(map[uuid.UUID]float64, len(averages))
+	for _, row := range averages {
+		averageByQuestion[row.QuestionID] = row.Average
+	}
+
+	for i, q := range formVersion.Questions {
+		questionSummary := QuestionSummary{
+			QuestionID:   q.QuestionID,
+			QuestionType: q.QuestionType,
+			QuestionText: q.QuestionText,
+			IsRequired:   q.IsRequired,
+			Asked:        askedByQuestion[q.QuestionID],
+			Answered:     answeredByQuestion[q.QuestionID],
+		}
+		questionSummary.AnswerRate = percentage(questionSummary.Answered, questionSummary.Asked)
+
+		switch q.QuestionType {
+		case question.QuestionTypeSelect, question.QuestionTypeMultiselect:
+			questionSummary.Options = make([]OptionSummary, len(q.Options))
+			for j, option := range q.Options {
+				count := optionsByQuestion[q.QuestionID][option.OptionID]
+				questionSummary.Options[j] = OptionSummary{
+					OptionID:   option.OptionID,
+					OptionText: option.OptionText,
+					Count:      count,
+					Percentage: percentage(count, questionSummary.Answered),
+				}
+			}
+		case question.QuestionTypeNumber, question.QuestionTypeRating, question.QuestionTypeLinearScale:
+			if average, ok := averageByQuestion[q.QuestionID]; ok {
+				average = math.Round(average*100) / 100
+				questionSummary.Average = &average
+			}
+			questionSummary.TopAnswers = topByQuestion[q.QuestionID]
+		default:
+			questionSummary.TopAnswers = topByQuestion[q.QuestionID]
+		}
+
+		response.Questions[i] = questionSummary
+	}
+
+	return response, nil
+}
+
+// percentage returns part as a percentage of total, rounded to two decimals.
+func percentage(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
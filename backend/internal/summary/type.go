@@ -0,0 +1,53 @@
+package summary
+
+import (
+	"database-final-project/internal/question"
+
+	"github.com/google/uuid"
+)
+
+// Response aggregates every submission of a form. Questions follow the latest
+// version of the form.
+type Response struct {
+	FormID            uuid.UUID         `json:"form_id"`
+	TotalResponses    int64             `json:"total_responses"`
+	ResponsesOverTime []DailyCount      `json:"responses_over_time"`
+	Questions         []QuestionSummary `json:"questions"`
+}
+
+// DailyCount is the number of submissions received on a UTC day.
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// QuestionSummary describes how a question was answered. Asked counts the
+// submissions whose form version contained the question, so questions added
+// later are not penalised for older responses.
+type QuestionSummary struct {
+	QuestionID   uuid.UUID             `json:"question_id"`
+	QuestionType question.QuestionType `json:"type"`
+	QuestionText string                `json:"question_text"`
+	IsRequired   bool                  `json:"is_required"`
+	Asked        int64                 `json:"asked"`
+	Answered     int64                 `json:"answered"`
+	AnswerRate   float64               `json:"answer_rate"`
+	Average      *float64              `json:"average,omitempty"`
+	Options      []OptionSummary       `json:"options,omitempty"`
+	TopAnswers   []TextCount           `json:"top_answers,omitempty"`
+}
+
+// OptionSummary counts how often an option was selected. Percentage is relative
+// to the submissions that answered the question, so multiselect options can add
+// up to more than 100.
+type OptionSummary struct {
+	OptionID   uuid.UUID `json:"option_id"`
+	OptionText string    `json:"option_text"`
+	Count      int64     `json:"count"`
+	Percentage float64   `json:"percentage"`
+}
+
+type TextCount struct {
+	Text  string `json:"text"`
+	Count int64  `json:"count"`
+}
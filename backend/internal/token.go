@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewToken returns a random, URL-safe secret for clients to present later.
+func NewToken() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// HashToken is what gets stored in place of a token, so a leaked table does
+// not hand out working credentials. Tokens carry enough entropy that a plain
+// SHA-256 is sufficient.
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,11 @@
+package user
+
+import "errors"
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrInvalidUsername    = errors.New("username must be 3 to 64 letters, digits, dots, dashes or underscores")
+	ErrInvalidPassword    = errors.New("password must be between 8 and 72 bytes long")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
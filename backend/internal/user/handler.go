@@ -0,0 +1,141 @@
+package user
+
+import (
+	"context"
+	"database-final-project/internal"
+	"database-final-project/internal/auth"
+	"database-final-project/internal/session"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CredentialsRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=64"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// SessionResponse is returned on sign-in; Token goes into the Authorization
+// header of later requests as "Bearer <token>".
+type SessionResponse struct {
+	session.Response
+	User Response `json:"user"`
+}
+
+type Store interface {
+	GetByID(ctx context.Context, id uuid.UUID) (Response, error)
+	Register(ctx context.Context, username string, password string) (Response, error)
+	Authenticate(ctx context.Context, username string, password string) (Response, error)
+}
+
+type sessionStore interface {
+	Create(ctx context.Context, userID uuid.UUID) (session.Response, error)
+	Revoke(ctx context.Context, token string) error
+}
+
+type Handler struct {
+	logger       *zap.Logger
+	store        Store
+	sessionStore sessionStore
+}
+
+func NewHandler(logger *zap.Logger, store Store, sessionStore sessionStore) *Handler {
+	return &Handler{
+		logger:       logger,
+		store:        store,
+		sessionStore: sessionStore,
+	}
+}
+
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	var req CredentialsRequest
+	err := internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	user, err := h.store.Register(r.Context(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidUsername) || errors.Is(err, ErrInvalidPassword) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+			return
+		}
+		if errors.Is(err, ErrUsernameTaken) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusConflict, internal.NewConflictError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to register user", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to register user"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, user)
+}
+
+func (h *Handler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	current, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Sign in required"))
+		return
+	}
+
+	user, err := h.store.GetByID(r.Context(), current.ID)
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to get user"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusOK, user)
+}
+
+// SignIn checks the credentials and starts a session.
+func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
+	var req CredentialsRequest
+	err := internal.ParseRequestFromBody(r, h.logger, &req)
+	if err != nil {
+		internal.WriteResponseToBody(w, h.logger, http.StatusBadRequest, internal.NewBadRequestError(err.Error()))
+		return
+	}
+
+	user, err := h.store.Authenticate(r.Context(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError(err.Error()))
+			return
+		}
+		h.logger.Error("Failed to authenticate user", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to sign in"))
+		return
+	}
+
+	s, err := h.sessionStore.Create(r.Context(), user.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create session", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to sign in"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusCreated, SessionResponse{Response: s, User: user})
+}
+
+// SignOut ends the session whose token authenticated the request.
+func (h *Handler) SignOut(w http.ResponseWriter, r *http.Request) {
+	token, ok := auth.BearerToken(r)
+	if !ok {
+		internal.WriteResponseToBody(w, h.logger, http.StatusUnauthorized, internal.NewUnauthorizedError("Sign in required"))
+		return
+	}
+
+	err := h.sessionStore.Revoke(r.Context(), token)
+	if err != nil {
+		h.logger.Error("Failed to revoke session", zap.Error(err))
+		internal.WriteResponseToBody(w, h.logger, http.StatusInternalServerError, internal.NewInternalServerError("Failed to sign out"))
+		return
+	}
+
+	internal.WriteResponseToBody(w, h.logger, http.StatusNoContent, nil)
+}
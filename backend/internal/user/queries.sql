@@ -0,0 +1,8 @@
+-- name: GetByID :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetByUsername :one
+SELECT * FROM users WHERE username = $1;
+
+-- name: Create :one
+INSERT INTO users (username, password_hash) VALUES ($1, $2) RETURNING *;
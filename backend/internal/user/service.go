@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,64}$`)
+
+// dummyHash is compared against when a username does not exist, so signing
+// in takes as long for unknown users as for wrong passwords.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+type Querier interface {
+	GetByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetByUsername(ctx context.Context, username string) (User, error)
+	Create(ctx context.Context, arg CreateParams) (User, error)
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (Response, error) {
+	user, err := s.queries.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Response{}, ErrUserNotFound
+		}
+		return Response{}, err
+	}
+
+	return toResponse(user), nil
+}
+
+// Register creates a user with a bcrypt hash of the password.
+func (s *Service) Register(ctx context.Context, username string, password string) (Response, error) {
+	if !usernamePattern.MatchString(username) {
+		return Response{}, ErrInvalidUsername
+	}
+	// bcrypt only looks at the first 72 bytes of a password.
+	if len(password) < 8 || len(password) > 72 {
+		return Response{}, ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return Response{}, err
+	}
+
+	user, err := s.queries.Create(ctx, CreateParams{
+		Username:     username,
+		PasswordHash: string(hash),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return Response{}, ErrUsernameTaken
+		}
+		return Response{}, err
+	}
+
+	return toResponse(user), nil
+}
+
+// Authenticate returns the user with the username when the password matches,
+// or ErrInvalidCredentials.
+func (s *Service) Authenticate(ctx context.Context, username string, password string) (Response, error) {
+	user, err := s.queries.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+			return Response{}, ErrInvalidCredentials
+		}
+		return Response{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return Response{}, ErrInvalidCredentials
+		}
+		return Response{}, err
+	}
+
+	return toResponse(user), nil
+}
+
+func toResponse(user User) Response {
+	return Response{
+		UserID:    user.ID,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,13 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+-- name: GetByFormID :many
+SELECT *
+FROM form_versions
+WHERE form_id = $1
+ORDER BY version ASC;
+
+-- name: GetLatestByFormID :one
+SELECT *
+FROM form_versions
+WHERE form_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: LockForm :exec
+SELECT id FROM forms WHERE id = $1 FOR NO KEY UPDATE;
+
+-- name: Create :one
+INSERT INTO form_versions (form_id, version, definition)
+VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM form_versions WHERE form_id = $1), $2)
+RETURNING *;
+
+-- name: GetByID :one
+SELECT *
+FROM form_versions
+WHERE id = $1;
+
+-- name: GetByIDs :many
+SELECT *
+FROM form_versions
+WHERE id = ANY (@ids::uuid[]);
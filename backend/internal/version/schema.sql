@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS form_versions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    version    INTEGER     NOT NULL,
+    definition JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (form_id, version)
+);
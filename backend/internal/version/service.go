@@ -0,0 +1,125 @@
+package version
+
+import (
+	"context"
+	"database-final-project/internal/question"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Querier interface {
+	GetByFormID(ctx context.Context, formID uuid.UUID) ([]FormVersion, error)
+	GetLatestByFormID(ctx context.Context, formID uuid.UUID) (FormVersion, error)
+	GetByID(ctx context.Context, id uuid.UUID) (FormVersion, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]FormVersion, error)
+	Create(ctx context.Context, arg CreateParams) (FormVersion, error)
+	LockForm(ctx context.Context, id uuid.UUID) error
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+}
+
+func NewService(logger *zap.Logger, queries Querier) *Service {
+	return &Service{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// Publish stores questions as the next version of the form. It must run in
+// the transaction that changed the questions: the form row stays locked until
+// that commits, so concurrent publishes cannot pick the same version number.
+func (s *Service) Publish(ctx context.Context, formID uuid.UUID, questions []question.OptionsQuestion) (Response, error) {
+	if questions == nil {
+		questions = []question.OptionsQuestion{}
+	}
+
+	definition, err := json.Marshal(questions)
+	if err != nil {
+		return Response{}, err
+	}
+
+	err = s.queries.LockForm(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	formVersion, err := s.queries.Create(ctx, CreateParams{
+		FormID:     formID,
+		Definition: definition,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return toResponse(formVersion)
+}
+
+func (s *Service) GetLatest(ctx context.Context, formID uuid.UUID) (Response, error) {
+	formVersion, err := s.queries.GetLatestByFormID(ctx, formID)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return toResponse(formVersion)
+}
+
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (Response, error) {
+	formVersion, err := s.queries.GetByID(ctx, id)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return toResponse(formVersion)
+}
+
+// GetByIDs loads several versions in one query, in no particular order.
+func (s *Service) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]Response, error) {
+	formVersions, err := s.queries.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponses(formVersions)
+}
+
+func (s *Service) GetByFormID(ctx context.Context, formID uuid.UUID) ([]Response, error) {
+	formVersions, err := s.queries.GetByFormID(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponses(formVersions)
+}
+
+func toResponses(formVersions []FormVersion) ([]Response, error) {
+	var err error
+	responses := make([]Response, len(formVersions))
+	for i, formVersion := range formVersions {
+		responses[i], err = toResponse(formVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return responses, nil
+}
+
+func toResponse(formVersion FormVersion) (Response, error) {
+	var questions []question.OptionsQuestion
+	err := json.Unmarshal(formVersion.Definition, &questions)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		VersionID: formVersion.ID,
+		FormID:    formVersion.FormID,
+		Version:   formVersion.Version,
+		Questions: questions,
+	}, nil
+}
@@ -0,0 +1,15 @@
+package version
+
+import (
+	"database-final-project/internal/question"
+
+	"github.com/google/uuid"
+)
+
+// Response is an immutable snapshot of a form's question tree.
+type Response struct {
+	VersionID uuid.UUID                  `json:"version_id"`
+	FormID    uuid.UUID                  `json:"form_id"`
+	Version   int32                      `json:"version"`
+	Questions []question.OptionsQuestion `json:"questions"`
+}
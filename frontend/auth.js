@@ -0,0 +1,44 @@
+import { config } from "./config.js";
+
+const TOKEN_KEY = "sessionToken";
+
+export function getToken() {
+  return localStorage.getItem(TOKEN_KEY);
+}
+
+export function setToken(token) {
+  localStorage.setItem(TOKEN_KEY, token);
+}
+
+export function clearToken() {
+  localStorage.removeItem(TOKEN_KEY);
+}
+
+// fetch with the session token attached. Without a valid session the user
+// is sent to the sign-in page and brought back afterwards.
+export async function authFetch(url, options = {}) {
+  const headers = new Headers(options.headers || {});
+  const token = getToken();
+  if (token) {
+    headers.set("Authorization", `Bearer ${token}`);
+  }
+
+  const response = await fetch(url, { ...options, headers });
+  if (response.status === 401) {
+    clearToken();
+    const next = window.location.pathname + window.location.search;
+    window.location.href = `/login/?next=${encodeURIComponent(next)}`;
+  }
+  return response;
+}
+
+// End the session on the server and forget the token
+export async function signOut() {
+  if (getToken()) {
+    await fetch(`${config.apiBaseUrl}/api/sessions/current`, {
+      method: "DELETE",
+      headers: { Authorization: `Bearer ${getToken()}` },
+    });
+  }
+  clearToken();
+}
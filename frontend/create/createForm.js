@@ -1,4 +1,12 @@
 import { config } from "../config.js";
+import { authFetch } from "../auth.js";
+
+// Configuration fields per question type, the other types take none
+const CONFIG_FIELDS = {
+  number: ["min", "max", "step"],
+  rating: ["max"],
+  linear_scale: ["min", "max", "labels"],
+};
 
 class FormBuilder {
   constructor() {
@@ -28,9 +36,47 @@ class FormBuilder {
     this.questionCount = 1; // Account for the existing question
   }
 
+  // Show the configuration fields that apply to the question type
+  updateConfigFields(questionDiv) {
+    const type = questionDiv.querySelector('[name="questionType"]').value;
+    const configDiv = questionDiv.querySelector(".config");
+    if (!configDiv) return;
+
+    const fields = CONFIG_FIELDS[type] || [];
+    configDiv.style.display = fields.length ? "block" : "none";
+    configDiv.querySelector(".config-min").style.display = fields.includes("min") ? "inline" : "none";
+    configDiv.querySelector(".config-max").style.display = fields.includes("max") ? "inline" : "none";
+    configDiv.querySelector(".config-step").style.display = fields.includes("step") ? "inline" : "none";
+    configDiv.querySelector(".config-labels").style.display = fields.includes("labels") ? "block" : "none";
+  }
+
+  // Read the configuration of a question, or undefined when it has none
+  collectConfig(questionDiv, type) {
+    const fields = CONFIG_FIELDS[type] || [];
+    const config = {};
+    const number = (name) => {
+      const value = questionDiv.querySelector(`[name="${name}"]`).value;
+      return value === "" ? undefined : Number(value);
+    };
+    const text = (name) =>
+      questionDiv.querySelector(`[name="${name}"]`).value.trim() || undefined;
+
+    if (fields.includes("min")) config.min = number("configMin");
+    if (fields.includes("max")) config.max = number("configMax");
+    if (fields.includes("step")) config.step = number("configStep");
+    if (fields.includes("labels")) {
+      config.min_label = text("configMinLabel");
+      config.max_label = text("configMaxLabel");
+    }
+
+    const isEmpty = Object.values(config).every((v) => v === undefined);
+    return isEmpty ? undefined : config;
+  }
+
   initializeQuestionState(questionDiv) {
     const typeSelect = questionDiv.querySelector('[name="questionType"]');
     const optionsDiv = questionDiv.querySelector(".options");
+    this.updateConfigFields(questionDiv);
 
     if (typeSelect && optionsDiv) {
       // Trigger the change event to set the initial state
@@ -64,15 +110,36 @@ class FormBuilder {
       <label>Question:</label><br />
       <input type="text" name="questionText" required /><br />
       
+      <label>Help text (Markdown):</label><br />
+      <textarea name="questionDescription" rows="2"></textarea><br />
+      
       <label>Required:</label>
       <input type="checkbox" name="isRequired" /><br />
       
       <label>Type:</label>
       <select name="questionType">
         <option value="short_answer">Short Answer</option>
+        <option value="paragraph">Paragraph</option>
         <option value="select">Select</option>
         <option value="multiselect">Multiple Select</option>
+        <option value="number">Number</option>
+        <option value="email">Email</option>
+        <option value="url">URL</option>
+        <option value="date">Date</option>
+        <option value="time">Time</option>
+        <option value="rating">Rating</option>
+        <option value="linear_scale">Linear Scale</option>
       </select>
+
+      <div class="config" style="display: none; margin-top: 10px;">
+        <span class="config-min"><label>Min:</label> <input type="number" name="configMin" step="any" /></span>
+        <span class="config-max"><label>Max:</label> <input type="number" name="configMax" step="any" /></span>
+        <span class="config-step"><label>Step:</label> <input type="number" name="configStep" step="any" min="0" /></span>
+        <div class="config-labels">
+          <label>Min label:</label> <input type="text" name="configMinLabel" />
+          <label>Max label:</label> <input type="text" name="configMaxLabel" />
+        </div>
+      </div>
       
       <div class="options" style="display: none; margin-top: 10px;">
         <label>Options:</label><br />
@@ -109,6 +176,7 @@ class FormBuilder {
         typeSelect.value === "select" || typeSelect.value === "multiselect";
       optionsDiv.style.display = needsOptions ? "block" : "none";
       console.log("Options div display set to:", optionsDiv.style.display);
+      this.updateConfigFields(questionDiv);
 
       // Set required attribute on option inputs
       const optionInputs = optionsDiv.querySelectorAll('[name="option"]');
@@ -224,6 +292,9 @@ class FormBuilder {
       const questionText = questionDiv
         .querySelector('[name="questionText"]')
         .value.trim();
+      const description = questionDiv
+        .querySelector('[name="questionDescription"]')
+        .value.trim();
       const isRequired = questionDiv.querySelector(
         '[name="isRequired"]'
       ).checked;
@@ -238,6 +309,14 @@ class FormBuilder {
         is_required: isRequired,
         question_text: questionText,
       };
+      if (description) {
+        question.description = description;
+      }
+
+      const questionConfig = this.collectConfig(questionDiv, type);
+      if (questionConfig) {
+        question.config = questionConfig;
+      }
 
       // Add options only for select/multiselect types
       if (type === "select" || type === "multiselect") {
@@ -254,7 +333,7 @@ class FormBuilder {
 
         question.options = options;
       }
-      // For short_answer questions, don't add options property at all
+      // For the other question types, don't add options property at all
 
       return question;
     });
@@ -265,6 +344,7 @@ class FormBuilder {
 
     return {
       title: title,
+      description: document.getElementById("formDescription").value.trim(),
       questions: questions,
     };
   }
@@ -287,7 +367,7 @@ class FormBuilder {
 
       console.log("Submitting form data:", formData);
 
-      const response = await fetch(`${config.apiBaseUrl}/api/forms`, {
+      const response = await authFetch(`${config.apiBaseUrl}/api/forms`, {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
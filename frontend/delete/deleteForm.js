@@ -1,4 +1,5 @@
 import { config } from "../config.js";
+import { authFetch } from "../auth.js";
 
 class DeleteForm {
   constructor() {
@@ -42,7 +43,7 @@ class DeleteForm {
       }
 
       // Delete the form using the API with the provided ID
-      const deleteResp = await fetch(
+      const deleteResp = await authFetch(
         `${config.apiBaseUrl}/api/forms/${formId}`,
         {
           method: "DELETE",
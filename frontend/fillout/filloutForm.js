@@ -1,4 +1,5 @@
 import { config } from "../config.js";
+import { getToken } from "../auth.js";
 
 const API_BASE_URL = config.apiBaseUrl;
 
@@ -7,6 +8,18 @@ const API_BASE_URL = config.apiBaseUrl;
 // Global variables
 let currentFormData = null;
 let formId = null;
+// Set when editing an existing reply instead of submitting a new one
+let submissionId = null;
+// Index of the page shown, among the pages with visible questions
+let currentPage = 0;
+// Idempotency key of the current submission, kept while retrying the same
+// answers so a retry after a lost response is not stored twice
+let idempotencyKey = null;
+let idempotencyPayload = null;
+// Invitation token from the link to an invite-only form
+const invitation = new URLSearchParams(window.location.search).get(
+  "invitation"
+);
 
 // DOM elements
 const loadingDiv = document.getElementById("loadingDiv");
@@ -15,8 +28,19 @@ const errorMessage = document.getElementById("errorMessage");
 const formContainer = document.getElementById("formContainer");
 const successDiv = document.getElementById("successDiv");
 const formTitle = document.getElementById("formTitle");
+const formDescription = document.getElementById("formDescription");
 const questionsContainer = document.getElementById("questionsContainer");
 const filloutForm = document.getElementById("filloutForm");
+const sectionHeader = document.getElementById("sectionHeader");
+const sectionTitle = document.getElementById("sectionTitle");
+const sectionDescription = document.getElementById("sectionDescription");
+const pageIndicator = document.getElementById("pageIndicator");
+const backButton = document.getElementById("backButton");
+const nextButton = document.getElementById("nextButton");
+const submitButton = document.getElementById("submitButton");
+const respondentDiv = document.getElementById("respondentDiv");
+const respondentRequired = document.getElementById("respondentRequired");
+const respondentEmail = document.getElementById("respondentEmail");
 
 // Get form ID from URL parameters or show error
 function getFormId() {
@@ -34,22 +58,72 @@ function getFormId() {
   return formId;
 }
 
+// Load an existing reply from API
+async function loadSubmission(formId, submissionId) {
+  const response = await fetch(
+    `${API_BASE_URL}/api/forms/${formId}/answers/${submissionId}`
+  );
+  if (!response.ok) {
+    throw new Error(
+      response.status === 404
+        ? `Reply not found. The reply with ID "${submissionId}" does not exist.`
+        : `Failed to load reply: ${response.status} ${response.statusText}`
+    );
+  }
+  return response.json();
+}
+
+// Pre-fill the rendered questions with the answers of an existing reply
+function fillAnswers(answers) {
+  answers.forEach((answer) => {
+    const name = `question_${answer.question_id}`;
+    if (answer.answer_text) {
+      const input = document.querySelector(
+        `textarea[name="${name}"], input[name="${name}"]:not([type="radio"]):not([type="checkbox"])`
+      );
+      if (input) input.value = answer.answer_text;
+      const choice = document.querySelector(
+        `input[name="${name}"][value="${answer.answer_text}"]`
+      );
+      if (choice) choice.checked = true;
+    }
+    (answer.answer_options || []).forEach((optionId) => {
+      const input = document.querySelector(
+        `input[name="${name}"][value="${optionId}"]`
+      );
+      if (input) input.checked = true;
+    });
+  });
+}
+
 // Load form data from API
 async function loadFormData(id) {
   try {
     console.log("🔄 Loading form data for ID:", id);
 
-    const response = await fetch(`${API_BASE_URL}/api/forms/${id}`);
+    // Descriptions come back as sanitized HTML, so they can be shown as is
+    const params = new URLSearchParams({ html: "true" });
+    if (invitation) params.set("invitation", invitation);
+    const headers = {};
+    const token = getToken();
+    if (token) headers.Authorization = `Bearer ${token}`;
+    const response = await fetch(`${API_BASE_URL}/api/forms/${id}?${params}`, {
+      headers,
+    });
 
     if (!response.ok) {
       if (response.status === 404) {
         throw new Error(
-          `Form not found. The form with ID "${id}" does not exist.`
+          `Form not found. The form with ID "${id}" does not exist or is not accepting responses.`
         );
       } else if (response.status === 400) {
         throw new Error(
           `Invalid form ID format. Please check the form ID in the URL.`
         );
+      } else if (response.status === 403) {
+        throw new Error(
+          "This form is by invitation only. Please open it from the link in your invitation."
+        );
       } else {
         throw new Error(
           `Failed to load form: ${response.status} ${response.statusText}`
@@ -67,6 +141,27 @@ async function loadFormData(id) {
   }
 }
 
+// Escape text typed by the form author before it is put into HTML
+function escapeHtml(text) {
+  const div = document.createElement("div");
+  div.textContent = text ?? "";
+  return div.innerHTML.replace(/"/g, "&quot;");
+}
+
+// HTML attributes mirroring the question's validation rules. The server
+// enforces the rules either way, these only give earlier feedback.
+function validationAttributes(question) {
+  const rules = question.validation || {};
+  const attributes = [];
+  if (rules.min_length !== undefined) {
+    attributes.push(`minlength="${rules.min_length}"`);
+  }
+  if (rules.max_length !== undefined) {
+    attributes.push(`maxlength="${rules.max_length}"`);
+  }
+  return attributes.join(" ");
+}
+
 // Render the form questions
 function renderQuestions(questions) {
   console.log("🎨 Rendering questions:", questions);
@@ -77,12 +172,18 @@ function renderQuestions(questions) {
     const questionDiv = document.createElement("div");
     questionDiv.className = "question";
     questionDiv.dataset.questionId = question.question_id;
+    questionDiv.dataset.sectionId = question.section_id || "";
 
     let questionHtml = `
       <h3>Question ${index + 1} ${
       question.is_required ? '<span class="required">*</span>' : ""
     }</h3>
-      <p><strong>${question.question_text}</strong></p>
+      <p><strong>${escapeHtml(question.question_text)}</strong></p>
+      ${
+        question.description_html
+          ? `<div class="description">${question.description_html}</div>`
+          : ""
+      }
     `;
 
     // Render different input types based on question type
@@ -93,6 +194,7 @@ function renderQuestions(questions) {
             name="question_${question.question_id}" 
             placeholder="Enter your answer..." 
             rows="3"
+            ${validationAttributes(question)}
             ${question.is_required ? "required" : ""}
           ></textarea>
         `;
@@ -110,7 +212,7 @@ function renderQuestions(questions) {
                 id="${option.option_id}"
                 ${question.is_required ? "required" : ""}
               />
-              <label for="${option.option_id}">${option.option_text}</label>
+              <label for="${option.option_id}">${escapeHtml(option.option_text)}</label>
             </div>
           `;
         });
@@ -128,27 +230,241 @@ function renderQuestions(questions) {
                 value="${option.option_id}" 
                 id="${option.option_id}"
               />
-              <label for="${option.option_id}">${option.option_text}</label>
+              <label for="${option.option_id}">${escapeHtml(option.option_text)}</label>
             </div>
           `;
         });
         questionHtml += "</div>";
         break;
+
+      case "paragraph":
+        questionHtml += `
+          <textarea 
+            name="question_${question.question_id}" 
+            placeholder="Enter your answer..." 
+            rows="6"
+            ${validationAttributes(question)}
+            ${question.is_required ? "required" : ""}
+          ></textarea>
+        `;
+        break;
+
+      case "number":
+      case "email":
+      case "url":
+      case "date":
+      case "time": {
+        const config = question.config || {};
+        const bounds =
+          question.type === "number"
+            ? `${config.min !== undefined ? `min="${config.min}"` : ""}
+               ${config.max !== undefined ? `max="${config.max}"` : ""}
+               step="${config.step !== undefined ? config.step : "any"}"`
+            : "";
+        questionHtml += `
+          <input 
+            type="${question.type}" 
+            name="question_${question.question_id}" 
+            ${bounds}
+            ${validationAttributes(question)}
+            ${question.is_required ? "required" : ""}
+          />
+        `;
+        break;
+      }
+
+      case "rating":
+      case "linear_scale": {
+        const config = question.config || {};
+        const low = question.type === "rating" ? 1 : config.min ?? 1;
+        const high = config.max ?? 5;
+        questionHtml += '<div class="option-group scale-group">';
+        if (config.min_label) {
+          questionHtml += `<span class="scale-label">${escapeHtml(config.min_label)}</span>`;
+        }
+        for (let value = low; value <= high; value++) {
+          const id = `${question.question_id}_${value}`;
+          questionHtml += `
+            <span class="option-item">
+              <input 
+                type="radio" 
+                name="question_${question.question_id}" 
+                value="${value}" 
+                id="${id}"
+                ${question.is_required ? "required" : ""}
+              />
+              <label for="${id}">${question.type === "rating" ? "★".repeat(value) : value}</label>
+            </span>
+          `;
+        }
+        if (config.max_label) {
+          questionHtml += `<span class="scale-label">${escapeHtml(config.max_label)}</span>`;
+        }
+        questionHtml += "</div>";
+        break;
+      }
     }
 
     questionDiv.innerHTML = questionHtml;
     questionsContainer.appendChild(questionDiv);
   });
 
+  applyLogic();
+
   console.log("✅ Questions rendered successfully");
 }
 
+// Current answer to a question as entered on the page
+function currentAnswer(question) {
+  const name = `question_${question.question_id}`;
+  if (question.type === "select" || question.type === "multiselect") {
+    const checked = document.querySelectorAll(`input[name="${name}"]:checked`);
+    return { text: "", options: Array.from(checked).map((input) => input.value) };
+  }
+  const input = document.querySelector(
+    `textarea[name="${name}"], input[name="${name}"]:not([type="radio"]), input[name="${name}"]:checked`
+  );
+  return { text: input ? input.value.trim() : "", options: [] };
+}
+
+// Whether a logic condition holds, mirroring the server: only answers to
+// visible questions count
+function conditionHolds(condition, visible) {
+  const question = currentFormData.questions.find(
+    (q) => q.question_id === condition.question_id
+  );
+  const answer =
+    question && visible.has(question.question_id)
+      ? currentAnswer(question)
+      : { text: "", options: [] };
+  const answered = answer.text !== "" || answer.options.length > 0;
+
+  switch (condition.operator) {
+    case "selected":
+      return answer.options.includes(condition.option_id);
+    case "not_selected":
+      return !answer.options.includes(condition.option_id);
+    case "answered":
+      return answered;
+    case "not_answered":
+      return !answered;
+    case "equals":
+      return (
+        answered &&
+        answer.text.toLowerCase() === condition.value.trim().toLowerCase()
+      );
+    default:
+      return false;
+  }
+}
+
+// IDs of the questions shown for the current answers, following show_if
+// conditions and jumps in question order
+function visibleQuestionIds() {
+  const questions = currentFormData.questions;
+  const visible = new Set();
+  let next = 0;
+
+  questions.forEach((question, index) => {
+    const logic = question.logic || {};
+    if (index < next) return;
+
+    const conditions = logic.show_if || [];
+    if (conditions.length > 0) {
+      const shown =
+        logic.match === "any"
+          ? conditions.some((c) => conditionHolds(c, visible))
+          : conditions.every((c) => conditionHolds(c, visible));
+      if (!shown) return;
+    }
+    visible.add(question.question_id);
+
+    const jump = (logic.jumps || []).find((j) => conditionHolds(j.if, visible));
+    if (jump) {
+      if (jump.end) {
+        next = questions.length;
+      } else if (jump.to_section_id) {
+        next = questions.findIndex((q) => q.section_id === jump.to_section_id);
+      } else {
+        next = questions.findIndex((q) => q.question_id === jump.to_question_id);
+      }
+    }
+  });
+
+  return visible;
+}
+
+// Sections that still have a visible question, in order. A form without
+// sections is a single page.
+function visiblePages(visible) {
+  const sections = currentFormData.sections || [];
+  const pages = sections.filter((section) =>
+    section.questions.some((q) => visible.has(q.question_id))
+  );
+  return pages.length > 0 ? pages : [null];
+}
+
+// Show the visible questions of the current page and the matching page
+// controls. Hidden questions are not required and their answers are not
+// submitted; questions on other pages are checked when leaving their page.
+function applyLogic() {
+  const visible = visibleQuestionIds();
+  const pages = visiblePages(visible);
+  currentPage = Math.min(currentPage, pages.length - 1);
+  const page = pages[currentPage];
+
+  questionsContainer.querySelectorAll(".question").forEach((questionDiv) => {
+    const shown =
+      visible.has(questionDiv.dataset.questionId) &&
+      (!page || questionDiv.dataset.sectionId === page.section_id);
+    questionDiv.style.display = shown ? "" : "none";
+    questionDiv.querySelectorAll("input, textarea").forEach((input) => {
+      if (input.required) input.dataset.required = "true";
+      input.required = shown && input.dataset.required === "true";
+    });
+  });
+
+  sectionHeader.style.display = page ? "block" : "none";
+  if (page) {
+    sectionTitle.textContent = page.title;
+    sectionDescription.innerHTML = page.description_html || "";
+    pageIndicator.textContent = `Page ${currentPage + 1} of ${pages.length}`;
+  }
+
+  const isLastPage = currentPage === pages.length - 1;
+  backButton.style.display = currentPage > 0 ? "" : "none";
+  nextButton.style.display = isLastPage ? "none" : "";
+  submitButton.style.display = isLastPage ? "" : "none";
+}
+
+// Check the inputs of the current page before moving on
+function currentPageIsValid() {
+  const inputs = questionsContainer.querySelectorAll(
+    '.question:not([style*="none"]) input, .question:not([style*="none"]) textarea'
+  );
+  for (const input of inputs) {
+    if (!input.checkValidity()) {
+      input.reportValidity();
+      return false;
+    }
+  }
+  return true;
+}
+
+function goToPage(page) {
+  currentPage = page;
+  applyLogic();
+  window.scrollTo(0, 0);
+}
+
 // Collect form answers
 function collectAnswers() {
   const answers = [];
+  const visible = visibleQuestionIds();
 
   currentFormData.questions.forEach((question) => {
     const questionId = question.question_id;
+    if (!visible.has(questionId)) return;
     const answer = {
       question_id: questionId,
     };
@@ -186,6 +502,24 @@ function collectAnswers() {
         const checkboxes = document.querySelectorAll(
           `input[name="question_${questionId}"]:checked`
         );
+        const rules = question.validation || {};
+        if (
+          checkboxes.length > 0 &&
+          rules.min_selections !== undefined &&
+          checkboxes.length < rules.min_selections
+        ) {
+          throw new Error(
+            `Please select at least ${rules.min_selections} options for: "${question.question_text}"`
+          );
+        }
+        if (
+          rules.max_selections !== undefined &&
+          checkboxes.length > rules.max_selections
+        ) {
+          throw new Error(
+            `Please select at most ${rules.max_selections} options for: "${question.question_text}"`
+          );
+        }
         if (checkboxes.length > 0) {
           answer.answer_options = Array.from(checkboxes).map((cb) => cb.value);
           answers.push(answer);
@@ -195,6 +529,27 @@ function collectAnswers() {
           );
         }
         break;
+
+      case "paragraph":
+      case "number":
+      case "email":
+      case "url":
+      case "date":
+      case "time":
+      case "rating":
+      case "linear_scale":
+        const input = document.querySelector(
+          `textarea[name="question_${questionId}"], input[name="question_${questionId}"]:not([type="radio"]), input[name="question_${questionId}"]:checked`
+        );
+        if (input && input.value.trim()) {
+          answer.answer_text = input.value.trim();
+          answers.push(answer);
+        } else if (question.is_required) {
+          throw new Error(
+            `Please answer the required question: "${question.question_text}"`
+          );
+        }
+        break;
     }
   });
 
@@ -208,18 +563,48 @@ async function submitFormAnswers(formId, answers) {
     console.log("📋 Answers data:", answers);
 
     const payload = { answers };
+    if (respondentEmail.value.trim()) {
+      payload.respondent_email = respondentEmail.value.trim();
+    }
     console.log("📦 Payload to submit:", JSON.stringify(payload, null, 2));
 
-    const response = await fetch(
-      `${API_BASE_URL}/api/forms/${formId}/answers`,
-      {
-        method: "POST",
-        headers: {
-          "Content-Type": "application/json",
-        },
-        body: JSON.stringify(payload),
+    // Signed-in respondents are identified by their session
+    const headers = { "Content-Type": "application/json" };
+    const token = getToken();
+    if (token) headers.Authorization = `Bearer ${token}`;
+    if (!submissionId) {
+      const body = JSON.stringify(payload);
+      if (body !== idempotencyPayload) {
+        idempotencyKey = crypto.randomUUID();
+        idempotencyPayload = body;
       }
-    );
+      headers["Idempotency-Key"] = idempotencyKey;
+    }
+
+    const url = submissionId
+      ? `${API_BASE_URL}/api/forms/${formId}/answers/${submissionId}`
+      : `${API_BASE_URL}/api/forms/${formId}/answers${
+          invitation ? `?invitation=${encodeURIComponent(invitation)}` : ""
+        }`;
+    const response = await fetch(url, {
+      method: submissionId ? "PUT" : "POST",
+      headers,
+      body: JSON.stringify(payload),
+    });
+    if (response.status === 422) {
+      const problem = await response.json();
+      const details = (problem.errors || []).map((e) => {
+        const question = currentFormData.questions.find(
+          (q) => q.question_id === e.field
+        );
+        return `${question ? question.question_text : e.field}: ${e.message}`;
+      });
+      throw new Error([problem.message, ...details].join("\n"));
+    }
+    if ([400, 401, 403, 409].includes(response.status)) {
+      const problem = await response.json();
+      throw new Error(problem.message);
+    }
     if (!response.ok) {
       throw new Error(`HTTP error! status: ${response.status}`);
     }
@@ -242,6 +627,21 @@ function showError(message) {
   errorDiv.style.display = "block";
 }
 
+// Why the form does not take new responses right now, or null when it does.
+// The server checks again on submit, including the response limit.
+function closedReason(form) {
+  const now = new Date();
+  if (form.status === "draft") return "This form is not published yet.";
+  if (form.status === "closed") return "This form is closed.";
+  if (form.opens_at && now < new Date(form.opens_at)) {
+    return `This form opens on ${new Date(form.opens_at).toLocaleString()}.`;
+  }
+  if (form.closes_at && now >= new Date(form.closes_at)) {
+    return "This form no longer accepts responses.";
+  }
+  return null;
+}
+
 // Show success message
 function showSuccess() {
   console.log("🎉 Showing success message");
@@ -255,6 +655,9 @@ function showSuccess() {
 async function initializeForm() {
   try {
     formId = getFormId();
+    submissionId = new URLSearchParams(window.location.search).get(
+      "submission"
+    );
 
     if (!formId) {
       throw new Error(
@@ -267,10 +670,44 @@ async function initializeForm() {
     // Load form data
     currentFormData = await loadFormData(formId);
 
+    const reason = submissionId ? null : closedReason(currentFormData);
+    if (reason) {
+      showError(reason);
+      return;
+    }
+
+    // Forms limited to one response per person only take signed-in users
+    if (
+      !submissionId &&
+      currentFormData.one_response_per_respondent &&
+      !getToken()
+    ) {
+      const next = window.location.pathname + window.location.search;
+      window.location.href = `/login/?next=${encodeURIComponent(next)}`;
+      return;
+    }
+
     // Display form
     formTitle.textContent = currentFormData.title;
+    formDescription.innerHTML = currentFormData.description_html || "";
     renderQuestions(currentFormData.questions);
 
+    // Forms that collect identity ask for an email, unless a signed-in
+    // respondent is identified by their session anyway
+    const identity = currentFormData.respondent_identity;
+    if (!submissionId && identity && identity !== "anonymous") {
+      const emailRequired = identity === "required" && !getToken();
+      respondentDiv.style.display = "block";
+      respondentRequired.style.display = emailRequired ? "inline" : "none";
+      respondentEmail.required = emailRequired;
+    }
+
+    if (submissionId) {
+      const submission = await loadSubmission(formId, submissionId);
+      fillAnswers(submission.answers || []);
+      applyLogic();
+    }
+
     // Hide loading and show form
     loadingDiv.style.display = "none";
     formContainer.style.display = "block";
@@ -282,9 +719,25 @@ async function initializeForm() {
   }
 }
 
+// Re-evaluate question logic whenever an answer changes
+questionsContainer.addEventListener("change", applyLogic);
+questionsContainer.addEventListener("input", applyLogic);
+
+backButton.addEventListener("click", () => goToPage(currentPage - 1));
+nextButton.addEventListener("click", () => {
+  if (currentPageIsValid()) goToPage(currentPage + 1);
+});
+
 // Handle form submission
 filloutForm.addEventListener("submit", async (e) => {
   e.preventDefault();
+
+  // Pressing enter before the last page moves on instead of submitting
+  if (nextButton.style.display !== "none") {
+    if (currentPageIsValid()) goToPage(currentPage + 1);
+    return;
+  }
+
   console.log("📝 Form submission started");
 
   try {
@@ -1,4 +1,5 @@
 import { config } from "./config.js";
+import { authFetch, signOut } from "./auth.js";
 
 const API_BASE_URL = config.apiBaseUrl;
 
@@ -12,17 +13,28 @@ const formsContainer = document.getElementById("formsContainer");
 const noFormsDiv = document.getElementById("noFormsDiv");
 const refreshFormsBtn = document.getElementById("refreshFormsBtn");
 const retryBtn = document.getElementById("retryBtn");
+const signOutBtn = document.getElementById("signOutBtn");
 
 // Fetch all forms from API
 async function fetchAllForms() {
   try {
     console.log("🔄 Fetching all forms from API...");
 
-    const response = await fetch(`${API_BASE_URL}/api/forms`);
-    if (!response.ok) {
-      throw new Error(`HTTP error! status: ${response.status}`);
-    }
-    const formsData = await response.json();
+    // The API is paginated, follow next_cursor until every page is loaded
+    const formsData = [];
+    let cursor = "";
+    do {
+      const params = new URLSearchParams({ limit: "100" });
+      if (cursor) params.set("cursor", cursor);
+
+      const response = await authFetch(`${API_BASE_URL}/api/forms?${params}`);
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+      const page = await response.json();
+      formsData.push(...page.forms);
+      cursor = page.next_cursor;
+    } while (cursor);
 
     console.log("✅ Forms data loaded:", formsData);
     return formsData;
@@ -169,6 +181,12 @@ function initializePage() {
     loadForms();
   });
 
+  signOutBtn.addEventListener("click", async (e) => {
+    e.preventDefault();
+    await signOut();
+    window.location.href = "/login/";
+  });
+
   console.log("✅ Page initialization completed");
 }
 
@@ -0,0 +1,59 @@
+import { config } from "../config.js";
+import { setToken } from "../auth.js";
+
+const loginForm = document.getElementById("loginForm");
+const registerButton = document.getElementById("registerButton");
+const errorMessage = document.getElementById("errorMessage");
+
+function credentials() {
+  return {
+    username: document.getElementById("username").value.trim(),
+    password: document.getElementById("password").value,
+  };
+}
+
+// Only follow redirects within this site
+function nextPage() {
+  const next = new URLSearchParams(window.location.search).get("next");
+  return next && next.startsWith("/") && !next.startsWith("//") ? next : "/";
+}
+
+async function post(path, body) {
+  const response = await fetch(`${config.apiBaseUrl}${path}`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(body),
+  });
+  const data = await response.json();
+  if (!response.ok) {
+    throw new Error(data.message || `HTTP error! status: ${response.status}`);
+  }
+  return data;
+}
+
+async function signIn() {
+  const session = await post("/api/sessions", credentials());
+  setToken(session.token);
+  window.location.href = nextPage();
+}
+
+loginForm.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  errorMessage.textContent = "";
+  try {
+    await signIn();
+  } catch (error) {
+    errorMessage.textContent = error.message;
+  }
+});
+
+registerButton.addEventListener("click", async () => {
+  errorMessage.textContent = "";
+  if (!loginForm.reportValidity()) return;
+  try {
+    await post("/api/users", credentials());
+    await signIn();
+  } catch (error) {
+    errorMessage.textContent = error.message;
+  }
+});
@@ -1,4 +1,5 @@
 import { config } from "../config.js";
+import { authFetch } from "../auth.js";
 
 class UpdateForm {
   constructor() {
@@ -37,7 +38,7 @@ class UpdateForm {
         submitBtn.textContent = "Updating...";
       }
 
-      const response = await fetch(`${config.apiBaseUrl}/api/forms/${formId}`, {
+      const response = await authFetch(`${config.apiBaseUrl}/api/forms/${formId}`, {
         method: "PUT",
         headers: {
           "Content-Type": "application/json",
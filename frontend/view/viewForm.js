@@ -1,4 +1,5 @@
 import { config } from "../config.js";
+import { authFetch } from "../auth.js";
 
 class ViewFormReplies {
   constructor() {
@@ -8,6 +9,8 @@ class ViewFormReplies {
     this.replyChooser = document.getElementById("replyChooser");
     this.replySelect = document.getElementById("replySelect");
     this.proceedReplyUpdateBtn = document.getElementById("proceedReplyUpdate");
+    this.deleteReplyBtn = document.getElementById("deleteReply");
+    this.currentFormId = null;
 
     this.formMetadata = null;
     this.replies = [];
@@ -40,12 +43,28 @@ class ViewFormReplies {
       this.replySelect.addEventListener("change", () => {
         const selected = this.replySelect.value;
         this.proceedReplyUpdateBtn.disabled = !selected;
+        if (this.deleteReplyBtn) this.deleteReplyBtn.disabled = !selected;
+        this.scrollToReplyCard(selected);
       });
 
+      // Editing reuses the fill-out page, which updates the reply in place
       this.proceedReplyUpdateBtn.addEventListener("click", () => {
         const selected = this.replySelect?.value;
-        if (!selected) return;
-        this.scrollToReplyCard(selected);
+        if (!selected || !this.currentFormId) return;
+        const params = new URLSearchParams({
+          id: this.currentFormId,
+          submission: selected,
+        });
+        window.location.href = `../fillout/index.html?${params}`;
+      });
+    }
+
+    if (this.replySelect && this.deleteReplyBtn) {
+      this.deleteReplyBtn.addEventListener("click", async () => {
+        const selected = this.replySelect?.value;
+        if (!selected || !this.currentFormId) return;
+        if (!confirm("Delete this reply? This cannot be undone.")) return;
+        await this.deleteReply(this.currentFormId, selected);
       });
     }
   }
@@ -74,6 +93,31 @@ class ViewFormReplies {
     return status;
   }
 
+  // The answers endpoint is paginated, follow next_cursor until every page is loaded
+  async fetchAllReplies(formId) {
+    const replies = [];
+    let cursor = "";
+    do {
+      const params = new URLSearchParams({ limit: "100" });
+      if (cursor) params.set("cursor", cursor);
+
+      const repliesResp = await authFetch(
+        `${config.apiBaseUrl}/api/forms/${formId}/answers?${params}`
+      );
+      if (!repliesResp.ok) {
+        const errorText = await repliesResp.text();
+        throw new Error(
+          `Failed to load replies: ${repliesResp.status} ${errorText}`
+        );
+      }
+      const page = await repliesResp.json();
+      replies.push(...page.submissions);
+      cursor = page.next_cursor;
+    } while (cursor);
+
+    return replies;
+  }
+
   async loadAllReplies() {
     try {
       if (this.loadAllRepliesBtn) {
@@ -84,14 +128,25 @@ class ViewFormReplies {
       this.setStatus("Fetching all forms...");
 
       // First, fetch all forms
-      const formsResp = await fetch(`${config.apiBaseUrl}/api/forms`);
-      if (!formsResp.ok) {
-        const errorText = await formsResp.text();
-        throw new Error(
-          `Failed to load forms: ${formsResp.status} ${errorText}`
+      const forms = [];
+      let cursor = "";
+      do {
+        const params = new URLSearchParams({ limit: "100" });
+        if (cursor) params.set("cursor", cursor);
+
+        const formsResp = await authFetch(
+          `${config.apiBaseUrl}/api/forms?${params}`
         );
-      }
-      const forms = await formsResp.json();
+        if (!formsResp.ok) {
+          const errorText = await formsResp.text();
+          throw new Error(
+            `Failed to load forms: ${formsResp.status} ${errorText}`
+          );
+        }
+        const page = await formsResp.json();
+        forms.push(...page.forms);
+        cursor = page.next_cursor;
+      } while (cursor);
 
       if (!forms || forms.length === 0) {
         this.setStatus("No forms found.");
@@ -107,19 +162,14 @@ class ViewFormReplies {
       for (const form of forms) {
         const formId = form.form_id || form.id;
         try {
-          const repliesResp = await fetch(
-            `${config.apiBaseUrl}/api/forms/${formId}/answers`
-          );
-          if (repliesResp.ok) {
-            const replies = await repliesResp.json();
-            if (replies && replies.length > 0) {
-              allRepliesData.push({
-                formId: formId,
-                formTitle: form.title || `Form ${formId}`,
-                formMetadata: form,
-                replies: replies,
-              });
-            }
+          const replies = await this.fetchAllReplies(formId);
+          if (replies.length > 0) {
+            allRepliesData.push({
+              formId: formId,
+              formTitle: form.title || `Form ${formId}`,
+              formMetadata: form,
+              replies: replies,
+            });
           }
         } catch (error) {
           console.error(`Error loading replies for form ${formId}:`, error);
@@ -255,6 +305,23 @@ class ViewFormReplies {
     });
   }
 
+  async deleteReply(formId, submissionId) {
+    try {
+      const resp = await authFetch(
+        `${config.apiBaseUrl}/api/forms/${formId}/answers/${submissionId}`,
+        { method: "DELETE" }
+      );
+      if (!resp.ok) {
+        const errorText = await resp.text();
+        throw new Error(`${resp.status} ${errorText}`);
+      }
+      await this.loadReplies(formId);
+    } catch (error) {
+      console.error("Error deleting reply:", error);
+      alert(`Error deleting reply: ${error.message}`);
+    }
+  }
+
   async loadReplies(formId) {
     try {
       if (this.loadRepliesBtn) {
@@ -264,24 +331,15 @@ class ViewFormReplies {
 
       this.setStatus("Fetching form and replies...");
 
-      const formResp = await fetch(`${config.apiBaseUrl}/api/forms/${formId}`);
+      const formResp = await authFetch(`${config.apiBaseUrl}/api/forms/${formId}`);
       if (!formResp.ok) {
         const errorText = await formResp.text();
         throw new Error(`Form not found: ${formResp.status} ${errorText}`);
       }
       this.formMetadata = await formResp.json();
+      this.currentFormId = formId;
 
-      const repliesResp = await fetch(
-        `${config.apiBaseUrl}/api/forms/${formId}/answers`
-      );
-      if (!repliesResp.ok) {
-        const errorText = await repliesResp.text();
-        throw new Error(
-          `Failed to load replies: ${repliesResp.status} ${errorText}`
-        );
-      }
-
-      this.replies = (await repliesResp.json()) || [];
+      this.replies = await this.fetchAllReplies(formId);
 
       this.populateRepliesDropdown(this.replies);
       this.renderReplyCards(this.replies);
@@ -332,6 +390,7 @@ class ViewFormReplies {
 
     this.replySelect.disabled = replies.length === 0;
     if (this.proceedReplyUpdateBtn) this.proceedReplyUpdateBtn.disabled = true;
+    if (this.deleteReplyBtn) this.deleteReplyBtn.disabled = true;
   }
 
   renderReplyCards(replies) {